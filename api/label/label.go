@@ -30,10 +30,72 @@ const (
 	// AnnForceUpgradeVal is tc annotation value to indicate whether force upgrade should be done
 	AnnForceUpgradeVal = "true"
 
+	// AnnForceDeleteKey is tc annotation key to indicate whether running jobs should be canceled on deletion
+	AnnForceDeleteKey = "tiflow.pingcap.com/force-delete"
+	// AnnForceDeleteVal is tc annotation value to indicate whether running jobs should be canceled on deletion
+	AnnForceDeleteVal = "true"
+
+	// AnnPauseReconcileKey is tc annotation key to indicate whether the operator should stop mutating the cluster
+	AnnPauseReconcileKey = "tiflow.pingcap.com/pause-reconcile"
+	// AnnPauseReconcileVal is tc annotation value to indicate whether the operator should stop mutating the cluster
+	AnnPauseReconcileVal = "true"
+
+	// AnnDisableAutoRollbackKey is tc annotation key to indicate whether failed upgrades should be left as they are
+	AnnDisableAutoRollbackKey = "tiflow.pingcap.com/disable-auto-rollback"
+	// AnnDisableAutoRollbackVal is tc annotation value to indicate whether failed upgrades should be left as they are
+	AnnDisableAutoRollbackVal = "true"
+
+	// AnnForceDowngradeKey is tc annotation key to indicate whether components may be downgraded
+	AnnForceDowngradeKey = "tiflow.pingcap.com/force-downgrade"
+	// AnnForceDowngradeVal is tc annotation value to indicate whether components may be downgraded
+	AnnForceDowngradeVal = "true"
+
+	// AnnRestartedAtKey is the annotation key of components, changing its value restarts the pods of the component
+	// gracefully, e.g. spec.executor.annotations."tiflow.pingcap.com/restartedAt": "2022-10-01T00:00:00Z"
+	AnnRestartedAtKey = "tiflow.pingcap.com/restartedAt"
+
+	// AnnDebugModeKey is pod annotation key to put a single pod into debug mode, the container is restarted
+	// without starting tiflow, removing it or setting it to other values brings the pod back
+	AnnDebugModeKey = "tiflow.pingcap.com/debug-mode"
+	// AnnDebugModeVal is pod annotation value to put a single pod into debug mode
+	AnnDebugModeVal = "true"
+
+	// AnnDeletePodKey is pod annotation key to delete a single pod gracefully, it's recreated by the statefulSet
+	AnnDeletePodKey = "tiflow.pingcap.com/delete-pod"
+	// AnnDeletePodVal is pod annotation value to delete a single pod gracefully
+	AnnDeletePodVal = "true"
+
+	// AnnEvictLeaderKey is pod annotation key to evict the leader from a single tiflow-master pod
+	AnnEvictLeaderKey = "tiflow.pingcap.com/evict-leader"
+	// AnnEvictLeaderVal is pod annotation value to evict the leader from a single tiflow-master pod
+	AnnEvictLeaderVal = "true"
+
+	// AnnPreferredLeaderKey is pod annotation key to prefer the tiflow-master pod as the leader
+	AnnPreferredLeaderKey = "tiflow.pingcap.com/preferred-leader"
+	// AnnPreferredLeaderVal is pod annotation value to prefer the tiflow-master pod as the leader
+	AnnPreferredLeaderVal = "true"
+
+	// AnnRunModeKey is pod annotation key read by the start scripts from the downward API annotations file
+	AnnRunModeKey = "runmode"
+	// AnnRunModeDebug is pod annotation value to start the container in debug mode
+	AnnRunModeDebug = "debug"
+
+	// TiflowClusterFinalizer is the finalizer used to tear down tiflow cluster gracefully before it is removed
+	TiflowClusterFinalizer = "tiflow.pingcap.com/finalizer"
+
+	// PodServiceLabelKey is the label key of services created for each tiflow-executor Pod
+	PodServiceLabelKey = "tiflow.pingcap.com/pod-service"
+	// PodServiceLabelVal is the label value of services created for each tiflow-executor Pod
+	PodServiceLabelVal = "true"
+
 	// TiflowMasterLabelVal is tiflow-master label value
 	TiflowMasterLabelVal string = "tiflow-master"
 	// TiflowExecutorLabelVal is tiflow-executor label value
 	TiflowExecutorLabelVal string = "tiflow-executor"
+	// DiscoveryLabelVal is discovery label value
+	DiscoveryLabelVal string = "discovery"
+	// MonitorLabelVal is tiflow-monitor label value
+	MonitorLabelVal string = "monitor"
 
 	// AnnStsLastSyncTimestamp is sts annotation key to indicate the last timestamp the operator sync the sts
 	AnnStsLastSyncTimestamp = "tidb.pingcap.com/sync-timestamp"
@@ -55,6 +117,14 @@ func New() Label {
 	}
 }
 
+// NewMonitor initialize a new Label for components of tiflow monitor
+func NewMonitor() Label {
+	return Label{
+		NameLabelKey:      "tiflow-monitor",
+		ManagedByLabelKey: TiFlowOperator,
+	}
+}
+
 // Instance adds instance kv pair to label
 func (l Label) Instance(name string) Label {
 	l[InstanceLabelKey] = name
@@ -93,6 +163,16 @@ func (l Label) IsTiflowExecutor() bool {
 	return l[ComponentLabelKey] == TiflowExecutorLabelVal
 }
 
+// Discovery assigns discovery to component key in label
+func (l Label) Discovery() Label {
+	return l.Component(DiscoveryLabelVal)
+}
+
+// Monitor assigns monitor to component key in label
+func (l Label) Monitor() Label {
+	return l.Component(MonitorLabelVal)
+}
+
 // Selector gets labels.Selector from label
 func (l Label) Selector() (labels.Selector, error) {
 	return metav1.LabelSelectorAsSelector(l.LabelSelector())
@@ -43,6 +43,19 @@ func TestLabelDMWorker(t *testing.T) {
 	require.True(t, l.IsTiflowExecutor())
 }
 
+func TestLabelDiscovery(t *testing.T) {
+	l := New()
+	l.Discovery()
+	require.Equal(t, "discovery", l[ComponentLabelKey])
+}
+
+func TestLabelMonitor(t *testing.T) {
+	l := NewMonitor()
+	l.Monitor()
+	require.Equal(t, "tiflow-monitor", l[NameLabelKey])
+	require.Equal(t, "monitor", l[ComponentLabelKey])
+}
+
 func TestLabelSelector(t *testing.T) {
 	l := New()
 	l.TiflowMaster()
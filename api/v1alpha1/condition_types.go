@@ -1,11 +1,16 @@
 package v1alpha1
 
-// TiflowClusterConditionType type alias
+// TiflowClusterConditionType is the type of metav1.Condition in status.clusterConditions
 type TiflowClusterConditionType string
 
 const (
+	// Ready is true when the cluster is running as the spec of status.observedGeneration
+	Ready TiflowClusterConditionType = "Ready"
+
 	VersionChecked TiflowClusterConditionType = "VersionChecked"
 	LeaderChecked  TiflowClusterConditionType = "LeaderChecked"
+	// ReconcilePaused is true when the operator only refreshes status without mutating the cluster
+	ReconcilePaused TiflowClusterConditionType = "ReconcilePaused"
 
 	MastersInfoUpdatedChecked TiflowClusterConditionType = "MastersInfoUpdatedChecked"
 	MasterVersionChecked      TiflowClusterConditionType = "MasterVersionChecked"
@@ -21,4 +26,20 @@ const (
 	ExecutorPVCChecked          TiflowClusterConditionType = "ExecutorPVCChecked"
 	ExecutorMembersChecked      TiflowClusterConditionType = "ExecutorMembersChecked"
 	ExecutorSyncChecked         TiflowClusterConditionType = "ExecutorSyncChecked"
+	// ReferencedMasterChecked is false when executors are blocked by the master of the cluster referenced by spec.cluster
+	ReferencedMasterChecked TiflowClusterConditionType = "ReferencedMasterChecked"
+)
+
+// Reasons of the conditions which are set without a specific reason
+const (
+	ConditionSatisfiedReason   = "Satisfied"
+	ConditionUnsatisfiedReason = "Unsatisfied"
+	// ReconcilePausedReason is the reason of the ReconcilePaused condition while pausing is requested by annotation
+	ReconcilePausedReason = "PausedByAnnotation"
+	// SpecNotObservedReason is the reason of the Ready condition while the latest spec isn't acted on yet
+	SpecNotObservedReason = "SpecNotObserved"
+	// ReferencedClusterNotFoundReason is the reason of the ReferencedMasterChecked condition when spec.cluster doesn't exist
+	ReferencedClusterNotFoundReason = "ReferencedClusterNotFound"
+	// ReferencedMasterNotReadyReason is the reason of the ReferencedMasterChecked condition when its tiflow-master isn't running
+	ReferencedMasterNotReadyReason = "ReferencedMasterNotReady"
 )
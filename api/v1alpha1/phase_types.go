@@ -14,6 +14,10 @@ const (
 	ClusterUnknown TiflowClusterPhaseType = "Unknown"
 	// ClusterFailed indicates the state of operator is failed
 	ClusterFailed TiflowClusterPhaseType = "Failed"
+	// ClusterDeleting indicates the cluster is being torn down
+	ClusterDeleting TiflowClusterPhaseType = "Deleting"
+	// ClusterSuspended indicates the cluster is paused and its members are scaled to zero
+	ClusterSuspended TiflowClusterPhaseType = "Suspended"
 )
 
 // MasterPhaseType indicates the cluster's state of masters
@@ -29,6 +33,9 @@ const (
 	MasterScalingUp   MasterPhaseType = "ScalingUp"
 	MasterScalingDown MasterPhaseType = "ScalingDown"
 	MasterDeleting    MasterPhaseType = "Deleting"
+	MasterSuspending  MasterPhaseType = "Suspending"
+	MasterSuspended   MasterPhaseType = "Suspended"
+	MasterResuming    MasterPhaseType = "Resuming"
 	MasterFailed      MasterPhaseType = "Failed"
 	MasterUnknown     MasterPhaseType = "Unknown"
 )
@@ -46,6 +53,9 @@ const (
 	ExecutorScalingUp   ExecutorPhaseType = "ScalingUp"
 	ExecutorScalingDown ExecutorPhaseType = "ScalingDown"
 	ExecutorDeleting    ExecutorPhaseType = "Deleting"
+	ExecutorSuspending  ExecutorPhaseType = "Suspending"
+	ExecutorSuspended   ExecutorPhaseType = "Suspended"
+	ExecutorResuming    ExecutorPhaseType = "Resuming"
 	ExecutorFailed      ExecutorPhaseType = "Failed"
 	ExecutorUnknown     ExecutorPhaseType = "Unknown"
 )
@@ -12,17 +12,38 @@ const (
 	ScaleUpType
 	ScaleDownType
 	DeleteType
+	SuspendType
+	ResumeType
 )
 
+var syncTypeNames = []string{
+	"Create",
+	"Upgrade",
+	"ScaleOut",
+	"ScaleIn",
+	"ScaleUp",
+	"ScaleDown",
+	"Delete",
+	"Suspend",
+	"Resume",
+}
+
+func (a SyncTypeName) String() string {
+	if a < CreateType || a > ResumeType {
+		return "Unknown"
+	}
+	return syncTypeNames[a]
+}
+
 func (a SyncTypeName) GetMasterClusterPhase() MasterPhaseType {
-	if a < CreateType || a > DeleteType {
+	if a < CreateType || a > ResumeType {
 		return MasterUnknown
 	}
 	return syncNameToMasterPhase[a]
 }
 
 func (a SyncTypeName) GetExecutorClusterPhase() ExecutorPhaseType {
-	if a < CreateType || a > DeleteType {
+	if a < CreateType || a > ResumeType {
 		return ExecutorUnknown
 	}
 	return syncNameToExecutorPhase[a]
@@ -36,6 +57,8 @@ var syncNameToMasterPhase = map[SyncTypeName]MasterPhaseType{
 	ScaleUpType:   MasterScalingUp,
 	ScaleDownType: MasterScalingDown,
 	DeleteType:    MasterDeleting,
+	SuspendType:   MasterSuspending,
+	ResumeType:    MasterResuming,
 }
 
 var syncNameToExecutorPhase = map[SyncTypeName]ExecutorPhaseType{
@@ -46,4 +69,6 @@ var syncNameToExecutorPhase = map[SyncTypeName]ExecutorPhaseType{
 	ScaleUpType:   ExecutorScalingUp,
 	ScaleDownType: ExecutorScalingDown,
 	DeleteType:    ExecutorDeleting,
+	SuspendType:   ExecutorSuspending,
+	ResumeType:    ExecutorResuming,
 }
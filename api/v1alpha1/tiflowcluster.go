@@ -2,11 +2,20 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/pingcap/tiflow-operator/api/config"
 	"github.com/pingcap/tiflow-operator/api/label"
 )
 
+const (
+	defaultUpgradeTimeout = 15 * time.Minute
+	defaultMaxVersionSkew = 1
+)
+
 func (tc *TiflowCluster) GetInstanceName() string {
 	labels := tc.GetLabels()
 	if inst, ok := labels[label.InstanceLabelKey]; ok {
@@ -191,6 +200,11 @@ func (tc *TiflowCluster) IsClusterTLSEnabled() bool {
 	return tc.Spec.TLSCluster != nil && *tc.Spec.TLSCluster
 }
 
+// MonitoringEnabled returns whether ServiceMonitors of tiflow cluster should be created
+func (tc *TiflowCluster) MonitoringEnabled() bool {
+	return tc.Spec.Monitoring != nil && tc.Spec.Monitoring.Enabled
+}
+
 func (tc *TiflowCluster) AllMasterMembersReady() bool {
 	return int(tc.MasterStsDesiredReplicas()) == len(tc.Status.Master.Members)
 }
@@ -199,6 +213,24 @@ func (tc *TiflowCluster) Heterogeneous() bool {
 	return tc.Spec.Cluster != nil && len(tc.Spec.Cluster.Name) > 0
 }
 
+// ReferencedCluster returns the namespace and name of the TiflowCluster referenced by spec.cluster,
+// the namespace defaults to the namespace of tc if it's not set
+func (tc *TiflowCluster) ReferencedCluster() (string, string) {
+	if !tc.Heterogeneous() {
+		return "", ""
+	}
+	ns := tc.Spec.Cluster.Namespace
+	if ns == "" {
+		ns = tc.GetNamespace()
+	}
+	return ns, tc.Spec.Cluster.Name
+}
+
+// RemoteMaster returns whether the executors join a tiflow-master in another Kubernetes cluster
+func (tc *TiflowCluster) RemoteMaster() bool {
+	return tc.Heterogeneous() && len(tc.Spec.Cluster.MasterAddresses) > 0
+}
+
 func (tc *TiflowCluster) WithoutLocalMaster() bool {
 	return tc.Spec.Master == nil
 }
@@ -207,6 +239,61 @@ func (tc *TiflowCluster) WithoutLocalExecutor() bool {
 	return tc.Spec.Executor == nil
 }
 
+// IsPaused returns whether the tiflow cluster is asked to be suspended
+func (tc *TiflowCluster) IsPaused() bool {
+	return tc.Spec.Paused
+}
+
+// ReconcilePaused returns whether the tiflow cluster is annotated to pause reconciling
+func (tc *TiflowCluster) ReconcilePaused() bool {
+	val, ok := tc.GetAnnotations()[label.AnnPauseReconcileKey]
+	return ok && val == label.AnnPauseReconcileVal
+}
+
+// AutoRollbackEnabled returns whether the failed upgrades of the tiflow cluster are rolled back automatically
+func (tc *TiflowCluster) AutoRollbackEnabled() bool {
+	val, ok := tc.GetAnnotations()[label.AnnDisableAutoRollbackKey]
+	return !ok || val != label.AnnDisableAutoRollbackVal
+}
+
+// GetUpgradeTimeout returns how long an upgraded pod may fail before the upgrade is rolled back
+func (tc *TiflowCluster) GetUpgradeTimeout() time.Duration {
+	if tc.Spec.UpgradeTimeout == nil {
+		return defaultUpgradeTimeout
+	}
+	return tc.Spec.UpgradeTimeout.Duration
+}
+
+// GetMaxVersionSkew returns the maximum number of minor versions tiflow-executors may lag behind tiflow-masters
+func (tc *TiflowCluster) GetMaxVersionSkew() int {
+	if tc.Spec.MaxVersionSkew == nil {
+		return defaultMaxVersionSkew
+	}
+	return int(*tc.Spec.MaxVersionSkew)
+}
+
+// DowngradeForced returns whether the tiflow cluster is annotated to allow downgrading its components
+func (tc *TiflowCluster) DowngradeForced() bool {
+	val, ok := tc.GetAnnotations()[label.AnnForceDowngradeKey]
+	return ok && val == label.AnnForceDowngradeVal
+}
+
+// MasterVersion returns the desired version of tiflow-master
+func (tc *TiflowCluster) MasterVersion() string {
+	if tc.Spec.Master != nil && tc.Spec.Master.Version != nil {
+		return *tc.Spec.Master.Version
+	}
+	return tc.Spec.Version
+}
+
+// ExecutorVersion returns the desired version of tiflow-executor
+func (tc *TiflowCluster) ExecutorVersion() string {
+	if tc.Spec.Executor != nil && tc.Spec.Executor.Version != nil {
+		return *tc.Spec.Executor.Version
+	}
+	return tc.Spec.Version
+}
+
 func (tc *TiflowCluster) MasterIsAvailable() bool {
 	return tc.Status.Master.Leader.Id != ""
 }
@@ -223,7 +310,7 @@ func (tc *TiflowCluster) GetClusterStatus() *TiflowClusterStatus {
 	return &tc.Status
 }
 
-func (tc *TiflowCluster) GetClusterConditions() []TiflowClusterCondition {
+func (tc *TiflowCluster) GetClusterConditions() []metav1.Condition {
 	return tc.Status.ClusterConditions
 }
 
@@ -254,3 +341,53 @@ func (tc *TiflowCluster) GetExecutorPhase() ExecutorPhaseType {
 func (tc *TiflowCluster) GetClusterPhase() TiflowClusterPhaseType {
 	return tc.Status.ClusterPhase
 }
+
+// GetPartition returns the ordinal below which the pods of a component with replicas are kept in the old revision
+func (u *UpgradeSpec) GetPartition(replicas int32) int32 {
+	if u == nil {
+		return 0
+	}
+	var partition int32
+	if u.CanaryReplicas != nil {
+		partition = replicas - *u.CanaryReplicas
+	} else if u.Partition != nil {
+		partition = *u.Partition
+	}
+	if partition < 0 {
+		return 0
+	}
+	if partition > replicas {
+		return replicas
+	}
+	return partition
+}
+
+// IsPaused returns whether the upgrade is held by users
+func (u *UpgradeSpec) IsPaused() bool {
+	return u != nil && u.Pause
+}
+
+// GetObservationPeriod returns how long an upgraded pod should stay ready before the next pod is upgraded
+func (u *UpgradeSpec) GetObservationPeriod() time.Duration {
+	if u == nil || u.ObservationPeriod == nil {
+		return 0
+	}
+	return u.ObservationPeriod.Duration
+}
+
+// RolledBack returns whether the upgrade has been rolled back
+func (r *UpgradeRecord) RolledBack() bool {
+	return r != nil && r.RolledBackTemplateHash != ""
+}
+
+// GetMaxUnavailable returns the number of pods of a component with replicas which can be upgraded at the same time
+func (u *UpgradeSpec) GetMaxUnavailable(replicas int32) int32 {
+	if u == nil || u.MaxUnavailable == nil {
+		return 1
+	}
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(u.MaxUnavailable, int(replicas), false)
+	if err != nil || maxUnavailable < 1 {
+		return 1
+	}
+	return int32(maxUnavailable)
+}
@@ -7,6 +7,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestTiflowMasterIsAvailable(t *testing.T) {
@@ -50,6 +51,42 @@ func TestTiflowMasterIsAvailable(t *testing.T) {
 	}
 }
 
+func TestTiflowClusterReconcilePaused(t *testing.T) {
+	type testcase struct {
+		name         string
+		annotations  map[string]string
+		expectPaused bool
+	}
+	testFn := func(test *testcase) {
+		t.Log(test.name)
+
+		tc := newTiflowCluster()
+		tc.Annotations = test.annotations
+		require.Equal(t, test.expectPaused, tc.ReconcilePaused())
+	}
+	tests := []testcase{
+		{
+			name:         "no annotations",
+			annotations:  nil,
+			expectPaused: false,
+		},
+		{
+			name:         "pause-reconcile is not true",
+			annotations:  map[string]string{"tiflow.pingcap.com/pause-reconcile": "false"},
+			expectPaused: false,
+		},
+		{
+			name:         "pause-reconcile is true",
+			annotations:  map[string]string{"tiflow.pingcap.com/pause-reconcile": "true"},
+			expectPaused: true,
+		},
+	}
+
+	for i := range tests {
+		testFn(&tests[i])
+	}
+}
+
 func newTiflowCluster() *TiflowCluster {
 	return &TiflowCluster{
 		TypeMeta: metav1.TypeMeta{
@@ -72,3 +109,33 @@ func newTiflowCluster() *TiflowCluster {
 		},
 	}
 }
+
+func TestUpgradeSpecPartition(t *testing.T) {
+	var spec *UpgradeSpec
+	require.Equal(t, int32(0), spec.GetPartition(3))
+
+	partition, canary := int32(2), int32(1)
+	spec = &UpgradeSpec{Partition: &partition}
+	require.Equal(t, int32(2), spec.GetPartition(3))
+	require.Equal(t, int32(1), spec.GetPartition(1))
+
+	// canaryReplicas takes precedence over partition
+	spec.CanaryReplicas = &canary
+	require.Equal(t, int32(4), spec.GetPartition(5))
+	canary = 10
+	require.Equal(t, int32(0), spec.GetPartition(5))
+}
+
+func TestUpgradeSpecMaxUnavailable(t *testing.T) {
+	var spec *UpgradeSpec
+	require.Equal(t, int32(1), spec.GetMaxUnavailable(50))
+
+	maxUnavailable := intstr.FromString("20%")
+	spec = &UpgradeSpec{MaxUnavailable: &maxUnavailable}
+	require.Equal(t, int32(10), spec.GetMaxUnavailable(50))
+	// at least one pod is upgraded
+	require.Equal(t, int32(1), spec.GetMaxUnavailable(3))
+
+	maxUnavailable = intstr.FromInt(5)
+	require.Equal(t, int32(5), spec.GetMaxUnavailable(50))
+}
@@ -22,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/pingcap/tiflow-operator/api/config"
 )
@@ -88,6 +89,7 @@ type ComponentSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
 	// Annotations for the component. Merge into the cluster-level annotations if non-empty
+	// Changing tiflow.pingcap.com/restartedAt of them restarts the pods of the component in a rolling upgrade.
 	// Optional: Defaults to cluster-level setting
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
@@ -152,6 +154,24 @@ type ComponentSpec struct {
 	// +optional
 	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 
+	// ReadinessProbe of the component container. The pod is only ready after the tiflow server is able to serve.
+	// By default an HTTP GET against the status endpoint of the component is used, if TLS is enabled the endpoint is
+	// requested by a command in the container instead, which presents the client certificates kubelet doesn't have.
+	// Non-zero delays, timeout and thresholds override the defaults, so does the handler if set.
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+
+	// LivenessProbe of the component container, the container is restarted once it fails.
+	// Defaults to requesting the status endpoint of the component like readinessProbe, the command used if TLS
+	// is enabled always passes while the pod is in debug mode. It's overridden like readinessProbe.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+
+	// StartupProbe of the component container, which holds back the other probes while the tiflow server starts.
+	// Defaults to the same check as livenessProbe, overridden like readinessProbe.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
 	// StatefulSetUpdateStrategy indicates the StatefulSetUpdateStrategy that will be
 	// employed to update Pods in the StatefulSet when a revision is made to
 	// Template.
@@ -224,6 +244,107 @@ type ServiceSpec struct {
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen=true
 
+// PodDisruptionBudgetSpec describes the PodDisruptionBudget of a component
+type PodDisruptionBudgetSpec struct {
+	// MaxUnavailable is the number or percentage of Pods which can be unavailable at the same time
+	// Optional: Defaults to 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// UpgradeSpec controls the rolling upgrade of a component, whose pods are upgraded one by one from the highest ordinal down
+type UpgradeSpec struct {
+	// Partition keeps the pods with an ordinal below it in the old revision, like the partition of StatefulSet.
+	// Optional: Defaults to 0, which means all pods are upgraded
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Partition *int32 `json:"partition,omitempty"`
+
+	// CanaryReplicas is the number of pods to upgrade, counted from the highest ordinal.
+	// It takes precedence over partition if both are set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CanaryReplicas *int32 `json:"canaryReplicas,omitempty"`
+
+	// Pause holds the upgrade after the pod being upgraded is ready, until it's set to false.
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+
+	// ObservationPeriod is the minimum time an upgraded pod stays ready before the next pod is upgraded, e.g. 1h.
+	// Optional: Defaults to 0
+	// +optional
+	ObservationPeriod *metav1.Duration `json:"observationPeriod,omitempty"`
+
+	// MaxUnavailable is the number or percentage of pods which can be upgraded at the same time, e.g. 10 or 20%.
+	// It's only honored by tiflow-executor, whose pods are deleted by the operator to be upgraded together,
+	// tiflow-master is always upgraded one by one to keep its quorum.
+	// The PodDisruptionBudget of tiflow-executor is still respected, which needs to allow as many disruptions.
+	// Optional: Defaults to 1
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// UpgradeRecord records the revision a component is upgraded from, so that a failed upgrade can be rolled back
+type UpgradeRecord struct {
+	// FromRevision is the ControllerRevision of the StatefulSet before the upgrade
+	FromRevision string `json:"fromRevision"`
+	// FromImage is the image of the component before the upgrade
+	// +optional
+	FromImage string `json:"fromImage,omitempty"`
+	// ToRevision is the ControllerRevision of the StatefulSet being upgraded to
+	ToRevision string `json:"toRevision"`
+	// StartTime is the time the upgrade is started
+	StartTime metav1.Time `json:"startTime"`
+	// RolledBackTemplateHash is the hash of the pod template which has been rolled back,
+	// the template is not applied again until the spec of the component is changed.
+	// +optional
+	RolledBackTemplateHash string `json:"rolledBackTemplateHash,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// LeaderTransferRecord records the attempts to move the tiflow-master leader to a preferred member
+type LeaderTransferRecord struct {
+	// Attempts is how many times the leaders out of the preferred members have been asked to resign in a row
+	Attempts int32 `json:"attempts"`
+	// LastAttemptTime is the time the last leader was asked to resign
+	LastAttemptTime metav1.Time `json:"lastAttemptTime"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// IngressSpec describes the Ingress of a component
+type IngressSpec struct {
+	// Host is the fully qualified domain name the Ingress serves
+	Host string `json:"host"`
+
+	// TLSSecretName is the name of secret which stores the certificate of Host,
+	// TLS is terminated at the Ingress for Host if it's set.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Annotations of the Ingress, such as the ones configuring the ingress controller.
+	// If tlsCluster is enabled, the ingress controller should be told to talk to the backend through https.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressClassName is the name of IngressClass which implements the Ingress
+	// Optional: Defaults to the default IngressClass of the Kubernetes cluster
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
 // MasterSpec defines the desired state of tiflow master
 type MasterSpec struct {
 	ComponentSpec               `json:",inline"`
@@ -243,11 +364,28 @@ type MasterSpec struct {
 	// +optional
 	Service *ServiceSpec `json:"service,omitempty"`
 
+	// Ingress exposes the tiflow-master service out of the Kubernetes cluster,
+	// so that external clients are able to submit jobs through it.
+	// Optional: Defaults to nil, which means no Ingress is created
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+
 	// TLSClientSecretNames are the names of secrets which stores etcd/metastore client certificates
 	// that used by tiflow-master.
 	// +optional
 	TLSClientSecretNames []string `json:"tlsClientSecretNames,omitempty"`
 
+	// Upgrade controls the rolling upgrade of tiflow-masters, e.g. upgrade a part of them and observe for a while.
+	// +optional
+	Upgrade *UpgradeSpec `json:"upgrade,omitempty"`
+
+	// PreferredLeaderZones are the zones the tiflow-master leader is moved back to, e.g. after a zone failover.
+	// The zone of a tiflow-master is the topology.kubernetes.io/zone label of its node, a tiflow-master pod annotated
+	// with tiflow.pingcap.com/preferred-leader: "true" is preferred as well. As the next leader is elected randomly,
+	// leaders out of the preferred ones are asked to resign repeatedly with backoff until a preferred one takes over.
+	// +optional
+	PreferredLeaderZones []string `json:"preferredLeaderZones,omitempty"`
+
 	// MaxFailoverCount limit the max replicas could be added in failover, 0 means no failover.
 	// Optional: Defaults to 3
 	// +kubebuilder:validation:Minimum=0
@@ -305,11 +443,39 @@ type ExecutorSpec struct {
 	// +optional
 	DataSubDir string `json:"dataSubDir,omitempty"`
 
+	// Service defines a Kubernetes service load balancing all tiflow-executors,
+	// besides the headless service used by tiflow-executors themselves.
+	// Optional: Defaults to nil, which means no such service is created
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// PodService defines a Kubernetes service for each tiflow-executor Pod, which is useful to expose
+	// every executor through NodePort or LoadBalancer for data paths across Kubernetes clusters.
+	// NodePort, LoadBalancerIP and ClusterIP are ignored as they can't be shared by the services.
+	// Optional: Defaults to nil, which means no such service is created
+	// +optional
+	PodService *ServiceSpec `json:"podService,omitempty"`
+
+	// PodDisruptionBudget of tiflow-executors, which limits the executors disrupted by node drains at the same time.
+	// It's also respected by the operator when it upgrades tiflow-executors.
+	// +optional
+	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// Upgrade controls the rolling upgrade of tiflow-executors, e.g. upgrade a part of them and observe for a while.
+	// +optional
+	Upgrade *UpgradeSpec `json:"upgrade,omitempty"`
+
 	// TLSClientSecretNames are the names of secrets which stores upstream/downstream client certificates
 	// that used by tiflow-executor.
 	// +optional
 	TLSClientSecretNames []string `json:"tlsClientSecretNames,omitempty"`
 
+	// AdvertiseAddresses are the externally reachable addresses (host:port) advertised by tiflow-executors,
+	// the i-th address is used by the executor with ordinal i.
+	// Executors without a configured address advertise their in-cluster address.
+	// +optional
+	AdvertiseAddresses []string `json:"advertiseAddresses,omitempty"`
+
 	// Persistent volume reclaim policy applied to the PVs that consumed by tiflow cluster
 	// +kubebuilder:default=Retain
 	PVReclaimPolicy *corev1.PersistentVolumeReclaimPolicy `json:"pvReclaimPolicy,omitempty"`
@@ -342,6 +508,41 @@ type ClusterRef struct {
 	// ClusterDomain is the domain of TiflowCluster object
 	// +optional
 	ClusterDomain string `json:"clusterDomain,omitempty"`
+
+	// MasterAddresses are the externally reachable addresses (host:port) of the referenced tiflow-master,
+	// set it when the referenced cluster lives in another Kubernetes cluster.
+	// If set, executors join these addresses instead of the in-cluster service of the referenced cluster.
+	// +optional
+	MasterAddresses []string `json:"masterAddresses,omitempty"`
+
+	// TLSClientSecretName is the name of secret which stores the client certificates of the remote tiflow-master,
+	// it's only used together with MasterAddresses.
+	// It defaults to the client secret of the referenced cluster, `<name>-cluster-client-secret`, copied to
+	// the namespace of this cluster if spec.tlsCluster is enabled.
+	// The secret should contain ca.crt, tls.crt and tls.key.
+	// +optional
+	TLSClientSecretName string `json:"tlsClientSecretName,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// MonitoringSpec describes how tiflow components are scraped by Prometheus Operator
+type MonitoringSpec struct {
+	// Enabled creates ServiceMonitors for tiflow-master service and tiflow-executor headless service.
+	// ServiceMonitors are skipped if the monitoring.coreos.com CRDs are not installed.
+	// Optional: Defaults to false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Interval at which metrics should be scraped, such as 30s
+	// Optional: Defaults to the global scrape interval of Prometheus
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Additional labels of ServiceMonitors, they are usually used by Prometheus to select ServiceMonitors
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // +k8s:openapi-gen=true
@@ -364,6 +565,13 @@ type TiflowClusterSpec struct {
 	// +optional
 	Version string `json:"version"`
 
+	// Paused indicates whether the tiflow cluster is suspended.
+	// If set, tiflow-executors and then tiflow-masters will be scaled to zero, while ConfigMaps,
+	// Services and PVCs are kept. Unset it to resume the cluster in order.
+	// Optional: Defaults to false
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
 	// ImagePullPolicy of Tiflow cluster Pods
 	// +kubebuilder:default=IfNotPresent
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
@@ -379,6 +587,20 @@ type TiflowClusterSpec struct {
 	// related components to use the new ConfigMap, that is, the new configuration will be applied automatically.
 	ConfigUpdateStrategy ConfigUpdateStrategy `json:"configUpdateStrategy,omitempty"`
 
+	// UpgradeTimeout is how long an upgraded pod may stay not ready or out of the cluster membership,
+	// after which the StatefulSet template of the component is rolled back to the revision before the upgrade.
+	// The rollback can be disabled by annotating the cluster with tiflow.pingcap.com/disable-auto-rollback: "true".
+	// Optional: Defaults to 15m
+	// +optional
+	UpgradeTimeout *metav1.Duration `json:"upgradeTimeout,omitempty"`
+
+	// MaxVersionSkew is the maximum number of minor versions tiflow-executors may lag behind tiflow-masters.
+	// Versions which are not semantic versions, e.g. latest, are not checked.
+	// Optional: Defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxVersionSkew *int32 `json:"maxVersionSkew,omitempty"`
+
 	// Whether enable the TLS connection between Tiflow components
 	// Optional: Defaults to nil
 	// +optional
@@ -433,6 +655,11 @@ type TiflowClusterSpec struct {
 	// +optional
 	Cluster *ClusterRef `json:"cluster,omitempty"`
 
+	// Monitoring configures the ServiceMonitors of Tiflow cluster for Prometheus Operator
+	// If tlsCluster is enabled, metrics are scraped through https with the client certificates of the cluster
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
 	// PodSecurityContext of the component
 	// +optional
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
@@ -456,6 +683,8 @@ type MasterMember struct {
 	Name          string `json:"name,omitempty"`
 	ClientURL     string `json:"clientURL"`
 	MemberDeleted bool   `json:"memberDeleted,omitempty"`
+	// Health is whether the member serves the API and sees the leader of tiflow-master cluster
+	Health bool `json:"health"`
 	// Last time the health transitioned from one to another.
 	// +nullable
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
@@ -519,6 +748,40 @@ type ClusterSyncType struct {
 	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
+// MaxOperationHistory is the number of operations kept in the history of a component
+const MaxOperationHistory = 10
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// OperationRecord records an operation done by the operator on a component, such as an upgrade or a scaling
+type OperationRecord struct {
+	// Type of the operation, e.g. Upgrade, ScaleOut
+	Type string `json:"type"`
+	// StartTime is the time the operation is started
+	StartTime metav1.Time `json:"startTime"`
+	// EndTime is the time the operation is completed or failed, it's empty while the operation is ongoing
+	// +optional
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+	// FromReplicas is the number of replicas when the operation is started
+	// +optional
+	FromReplicas int32 `json:"fromReplicas,omitempty"`
+	// ToReplicas is the number of replicas when the operation is ended
+	// +optional
+	ToReplicas int32 `json:"toReplicas,omitempty"`
+	// FromImage is the image of the component when the operation is started
+	// +optional
+	FromImage string `json:"fromImage,omitempty"`
+	// ToImage is the image of the component when the operation is ended
+	// +optional
+	ToImage string `json:"toImage,omitempty"`
+	// Outcome of the operation: Ongoing, Completed or Failed
+	Outcome string `json:"outcome"`
+	// Message is the last message of the operation
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen=true
 
@@ -532,6 +795,12 @@ type MasterStatus struct {
 	PeerMembers    map[string]MasterMember `json:"peerMembers,omitempty"`
 	FailureMembers map[string]MasterMember `json:"failureMembers,omitempty"`
 	StatefulSet    *apps.StatefulSetStatus `json:"statefulSet,omitempty"`
+	// Upgrade records the ongoing or the last rolled back upgrade of tiflow-master
+	// +optional
+	Upgrade *UpgradeRecord `json:"upgrade,omitempty"`
+	// LeaderTransfer records the attempts to move the leader to a preferred tiflow-master
+	// +optional
+	LeaderTransfer *LeaderTransferRecord `json:"leaderTransfer,omitempty"`
 	// LastUpdateTime means the time when the status of Master cluster's info was updated
 	// +required
 	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
@@ -545,6 +814,10 @@ type MasterStatus struct {
 	Message string `json:"message,omitempty"`
 	// +nullable
 	SyncTypes []ClusterSyncType `json:"syncTypes,omitempty"`
+	// History records the last operations done on the component, the oldest ones are dropped
+	// once there are more than MaxOperationHistory.
+	// +optional
+	History []OperationRecord `json:"history,omitempty"`
 	// LastTransitionTime means the time when the status of Cluster Phase
 	// transitioned from one to another
 	// +required
@@ -564,6 +837,9 @@ type ExecutorStatus struct {
 	FailoverUID    types.UID                 `json:"failoverUID,omitempty"`
 	// Volumes contains the status of all volumes.
 	Volumes map[string]*StorageVolumeStatus `json:"volumes,omitempty"`
+	// Upgrade records the ongoing or the last rolled back upgrade of tiflow-executor
+	// +optional
+	Upgrade *UpgradeRecord `json:"upgrade,omitempty"`
 	// LastUpdateTime means the time when the status of Executor cluster's info was updated
 	// +required
 	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
@@ -577,6 +853,10 @@ type ExecutorStatus struct {
 	Message string `json:"message,omitempty"`
 	// +nullable
 	SyncTypes []ClusterSyncType `json:"syncTypes,omitempty"`
+	// History records the last operations done on the component, the oldest ones are dropped
+	// once there are more than MaxOperationHistory.
+	// +optional
+	History []OperationRecord `json:"history,omitempty"`
 	// LastTransitionTime means the time when the status of Executor Phase
 	// transitioned from one to another.
 	// +required
@@ -588,32 +868,23 @@ type ExecutorStatus struct {
 // +k8s:openapi-gen=true
 // +k8s:deepcopy-gen=true
 
-// TiflowClusterCondition is tiflow cluster condition
-type TiflowClusterCondition struct {
-	// Type of the condition.
-	Type TiflowClusterConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status metav1.ConditionStatus `json:"status"`
-	// LastTransitionTime means last time the condition transitioned from one status to another.
-	// +nullable
-	// +optional
-	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
-}
-
-// +k8s:openapi-gen=true
-// +k8s:deepcopy-gen=true
-
 // TiflowClusterStatus defines the observed state of TiflowCluster
 type TiflowClusterStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 	Master   MasterStatus   `json:"master,omitempty"`
 	Executor ExecutorStatus `json:"executor,omitempty"`
+	// ObservedGeneration is the most recent generation of the spec acted on by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// ClusterConditions list of conditions representing the current status of the tiflow engine resource
-	// Interact between Master and Executor, and will update each other
+	// Interact between Master and Executor, and will update each other.
+	// The Ready condition summarizes whether the cluster is running as the spec of observedGeneration.
 	// +optional
 	// +nullable
-	ClusterConditions []TiflowClusterCondition `json:"clusterConditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	ClusterConditions []metav1.Condition `json:"clusterConditions,omitempty"`
 	// ClusterPhase represents the observed state of a tiflow cluster
 	// Update by master's phase and executor's phase
 	// +required
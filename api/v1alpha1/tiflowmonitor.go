@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultPrometheusBaseImage = "prom/prometheus"
+	defaultPrometheusVersion   = "v2.37.0"
+	defaultGrafanaBaseImage    = "grafana/grafana"
+	defaultGrafanaVersion      = "9.1.0"
+)
+
+// PrometheusImage returns the image of Prometheus
+func (tm *TiflowMonitor) PrometheusImage() string {
+	return tm.Spec.Prometheus.image(defaultPrometheusBaseImage, defaultPrometheusVersion)
+}
+
+// PrometheusImagePullPolicy returns the image pull policy of Prometheus
+func (tm *TiflowMonitor) PrometheusImagePullPolicy() corev1.PullPolicy {
+	return tm.Spec.Prometheus.imagePullPolicy(tm.Spec.ImagePullPolicy)
+}
+
+// GrafanaEnabled returns whether Grafana should be deployed
+func (tm *TiflowMonitor) GrafanaEnabled() bool {
+	return tm.Spec.Grafana != nil
+}
+
+// GrafanaImage returns the image of Grafana
+func (tm *TiflowMonitor) GrafanaImage() string {
+	if !tm.GrafanaEnabled() {
+		return ""
+	}
+	return tm.Spec.Grafana.image(defaultGrafanaBaseImage, defaultGrafanaVersion)
+}
+
+// GrafanaImagePullPolicy returns the image pull policy of Grafana
+func (tm *TiflowMonitor) GrafanaImagePullPolicy() corev1.PullPolicy {
+	if !tm.GrafanaEnabled() {
+		return tm.Spec.ImagePullPolicy
+	}
+	return tm.Spec.Grafana.imagePullPolicy(tm.Spec.ImagePullPolicy)
+}
+
+// ClusterRefs returns the namespace and name of the scraped TiflowClusters,
+// the namespace defaults to the namespace of tm if it's not set
+func (tm *TiflowMonitor) ClusterRefs() []TiflowClusterRef {
+	refs := make([]TiflowClusterRef, 0, len(tm.Spec.Clusters))
+	for _, ref := range tm.Spec.Clusters {
+		if ref.Namespace == "" {
+			ref.Namespace = tm.GetNamespace()
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func (ref TiflowClusterRef) String() string {
+	return fmt.Sprintf("%s/%s", ref.Namespace, ref.Name)
+}
+
+func (c MonitorContainer) image(defaultBaseImage, defaultVersion string) string {
+	image := c.BaseImage
+	if image == "" {
+		image = defaultBaseImage
+	}
+	version := c.Version
+	if version == "" {
+		version = defaultVersion
+	}
+	return fmt.Sprintf("%s:%s", image, version)
+}
+
+func (c MonitorContainer) imagePullPolicy(defaultPolicy corev1.PullPolicy) corev1.PullPolicy {
+	if c.ImagePullPolicy != nil {
+		return *c.ImagePullPolicy
+	}
+	return defaultPolicy
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// TiflowMonitorSpec defines the desired state of TiflowMonitor
+type TiflowMonitorSpec struct {
+	// Clusters are the TiflowClusters scraped by this monitor.
+	// If a cluster enables tlsCluster, its client secret should exist in the namespace of the TiflowMonitor,
+	// named <cluster>-cluster-client-secret if the cluster is in the same namespace,
+	// otherwise <namespace>-<cluster>-cluster-client-secret.
+	// +kubebuilder:validation:MinItems=1
+	Clusters []TiflowClusterRef `json:"clusters"`
+
+	// Prometheus spec
+	Prometheus PrometheusSpec `json:"prometheus"`
+
+	// Grafana spec, Grafana is not deployed if it's not set
+	// +optional
+	Grafana *GrafanaSpec `json:"grafana,omitempty"`
+
+	// Persistent stores the data of Prometheus and Grafana in a PersistentVolumeClaim,
+	// otherwise the data is lost once the monitor Pod is recreated.
+	// Optional: Defaults to false
+	// +optional
+	Persistent bool `json:"persistent,omitempty"`
+
+	// The storageClassName of the persistent volume for monitor data storage.
+	// Defaults to Kubernetes default storage class.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Storage is the request storage size of the persistent volume for monitor data storage.
+	// +kubebuilder:default="10Gi"
+	// +optional
+	Storage string `json:"storage,omitempty"`
+
+	// ImagePullPolicy of the monitor Pod
+	// +kubebuilder:default=IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets is an optional list of references to secrets in the same namespace to use for pulling any of the images.
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// NodeSelector of the monitor Pod
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the monitor Pod
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// TiflowClusterRef reference to a TiflowCluster scraped by TiflowMonitor
+type TiflowClusterRef struct {
+	// Namespace is the namespace that TiflowCluster object locates,
+	// defaults to the namespace of TiflowMonitor
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of TiflowCluster object
+	Name string `json:"name"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// MonitorContainer is the common attributes of the containers of TiflowMonitor
+type MonitorContainer struct {
+	corev1.ResourceRequirements `json:",inline"`
+
+	// Base image of the container, the default one of each container is used if not set
+	// +optional
+	BaseImage string `json:"baseImage,omitempty"`
+
+	// Version of the image, the default one of each container is used if not set
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// ImagePullPolicy of the container, overrides spec.imagePullPolicy
+	// +optional
+	ImagePullPolicy *corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// PrometheusSpec is the desired state of Prometheus
+type PrometheusSpec struct {
+	MonitorContainer `json:",inline"`
+
+	// RetentionTime is how long the metrics are kept, such as 15d
+	// +kubebuilder:default="15d"
+	// +optional
+	RetentionTime string `json:"retentionTime,omitempty"`
+
+	// ScrapeInterval is how frequently the tiflow components are scraped, such as 15s
+	// +kubebuilder:default="15s"
+	// +optional
+	ScrapeInterval string `json:"scrapeInterval,omitempty"`
+
+	// Service of Prometheus
+	// +optional
+	Service ServiceSpec `json:"service,omitempty"`
+}
+
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+
+// GrafanaSpec is the desired state of Grafana
+type GrafanaSpec struct {
+	MonitorContainer `json:",inline"`
+
+	// AdminSecretName is the name of secret which stores the username and password of the Grafana admin,
+	// the secret should contain the keys username and password.
+	// Optional: Defaults to the builtin admin of Grafana
+	// +optional
+	AdminSecretName string `json:"adminSecretName,omitempty"`
+
+	// Service of Grafana
+	// +optional
+	Service ServiceSpec `json:"service,omitempty"`
+}
+
+// TiflowMonitorStatus defines the observed state of TiflowMonitor
+type TiflowMonitorStatus struct {
+	// PrometheusURL is the address to access Prometheus
+	// +optional
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+
+	// GrafanaURL is the address to access Grafana
+	// +optional
+	GrafanaURL string `json:"grafanaURL,omitempty"`
+
+	// Ready indicates whether the monitor Pod is available
+	// +optional
+	Ready bool `json:"ready,omitempty"`
+
+	// Clusters are the TiflowClusters which are scraped, in the form of namespace/name
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// (Optional) Message related to the status of the TiflowMonitor, such as the clusters which are not found
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+
+// TiflowMonitor is the Schema for the tiflowmonitors API
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen=true
+// +kubebuilder:resource:shortName="tfm"
+// +kubebuilder:printcolumn:name="Ready",type=boolean,JSONPath=`.status.ready`
+// +kubebuilder:printcolumn:name="Prometheus",type=string,JSONPath=`.status.prometheusURL`
+// +kubebuilder:printcolumn:name="Grafana",type=string,JSONPath=`.status.grafanaURL`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type TiflowMonitor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TiflowMonitorSpec   `json:"spec,omitempty"`
+	Status TiflowMonitorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TiflowMonitorList contains a list of TiflowMonitor
+type TiflowMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TiflowMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TiflowMonitor{}, &TiflowMonitorList{})
+}
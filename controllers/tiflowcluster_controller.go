@@ -18,38 +18,59 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
+
 	"github.com/go-logr/logr"
 	"github.com/lithammer/shortuuid/v3"
 	"go.uber.org/zap/zapcore"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"github.com/pingcap/tiflow-operator/api/label"
 	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
 	"github.com/pingcap/tiflow-operator/pkg/controller/tiflowcluster"
+	"github.com/pingcap/tiflow-operator/pkg/metrics"
 	"github.com/pingcap/tiflow-operator/pkg/result"
 	"github.com/pingcap/tiflow-operator/pkg/status"
 )
 
+// clusterRefIndexKey indexes TiflowClusters by the cluster referenced in spec.cluster
+const clusterRefIndexKey = ".spec.cluster"
+
 // TiflowClusterReconciler reconciles a TiflowCluster object
 type TiflowClusterReconciler struct {
 	client.Client
 	ClientSet kubernetes.Interface
 	Log       logr.Logger
 	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
 	Control   tiflowcluster.ControlInterface
 }
 
-func NewTiflowClusterReconciler(cli client.Client, clientSet kubernetes.Interface, scheme *runtime.Scheme) *TiflowClusterReconciler {
+func NewTiflowClusterReconciler(cli client.Client, clientSet kubernetes.Interface, scheme *runtime.Scheme, recorder record.EventRecorder) *TiflowClusterReconciler {
 	return &TiflowClusterReconciler{
 		Client:    cli,
 		ClientSet: clientSet,
 		Log:       ctrl.Log.WithName("controller").WithName("TiflowCluster"),
 		Scheme:    scheme,
-		Control:   tiflowcluster.NewDefaultTiflowClusterControl(cli, clientSet),
+		Recorder:  recorder,
+		Control:   tiflowcluster.NewDefaultTiflowClusterControl(cli, clientSet, recorder),
 	}
 }
 
@@ -59,13 +80,18 @@ func NewTiflowClusterReconciler(cli client.Client, clientSet kubernetes.Interfac
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets;services;persistentvolumeclaims;persistentvolumes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;update;delete
 // +kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=apps,resources=statefulsets;deployments,verbs=*
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/scale,verbs=get;watch;update
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;update;delete
 // +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;update;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=*
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -84,10 +110,33 @@ func (r *TiflowClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	tc := &pingcapcomv1alpha1.TiflowCluster{}
 	if err := r.Get(ctx, req.NamespacedName, tc); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.DeleteClusterMetrics(req.Namespace, req.Name)
+		}
 		log.Error(err, "failed to retrieve tiflow cluster resource")
 		return result.RequeueIfError(client.IgnoreNotFound(err))
 	}
 
+	if !tc.GetDeletionTimestamp().IsZero() {
+		return r.reconcileDeletion(ctx, log, tc)
+	}
+
+	start := time.Now()
+	defer func() {
+		metrics.ReconcileDuration.WithLabelValues(tc.GetNamespace(), tc.GetName()).Observe(time.Since(start).Seconds())
+		metrics.UpdateClusterMetrics(tc)
+	}()
+
+	if !controllerutil.ContainsFinalizer(tc, label.TiflowClusterFinalizer) {
+		log.Info("adding finalizer to tiflow cluster")
+		controllerutil.AddFinalizer(tc, label.TiflowClusterFinalizer)
+		if err := r.Update(ctx, tc); err != nil {
+			log.Error(err, "failed to add finalizer to tiflow cluster")
+			return result.RequeueIfError(err)
+		}
+		return result.RequeueImmediately()
+	}
+
 	if tc.Status.ClusterPhase == "" {
 		log.Info("reconciling tiflow cluster on first")
 		if err := r.updateTiflowClusterStatus(tc); err != nil {
@@ -99,6 +148,9 @@ func (r *TiflowClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	if err := r.Control.UpdateTiflowCluster(ctx, tc); err != nil {
 		log.Info("Error on TiflowCluster Reconcile ...")
+		if !controller.IsRequeueError(err) {
+			metrics.ReconcileErrors.WithLabelValues(tc.GetNamespace(), tc.GetName()).Inc()
+		}
 
 		defer func(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) {
 			if err := r.updateTiflowClusterStatus(tc); err != nil {
@@ -123,18 +175,146 @@ func (r *TiflowClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return result.RequeueIfError(err)
 	}
 
+	if tc.ReconcilePaused() {
+		// keep refreshing status while reconciling is paused
+		log.Info("reconciling is paused by annotation, only status is refreshed", "annotation", label.AnnPauseReconcileKey)
+		return result.RequeueAfter(result.LongPauseTime, nil)
+	}
+
+	return result.NoRequeue()
+}
+
+// reconcileDeletion tears down the tiflow cluster gracefully and removes the finalizer once it's done.
+func (r *TiflowClusterReconciler) reconcileDeletion(ctx context.Context, log logr.Logger, tc *pingcapcomv1alpha1.TiflowCluster) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(tc, label.TiflowClusterFinalizer) {
+		return result.NoRequeue()
+	}
+
+	if tc.ReconcilePaused() {
+		log.Info("reconciling is paused by annotation, skip tearing down tiflow cluster", "annotation", label.AnnPauseReconcileKey)
+		return result.RequeueAfter(result.LongPauseTime, nil)
+	}
+
+	log.Info("deleting tiflow cluster")
+	if err := r.Control.DeleteTiflowCluster(ctx, tc); err != nil {
+		if err := r.updateTiflowClusterStatus(tc); err != nil {
+			log.Error(err, "failed to update tiflow cluster status")
+		}
+
+		if controller.IsRequeueError(err) {
+			log.Info("tiflow cluster is still deleting", "reason", err.Error())
+			return result.RequeueAfter(result.ShortPauseTime, nil)
+		}
+		metrics.ReconcileErrors.WithLabelValues(tc.GetNamespace(), tc.GetName()).Inc()
+		return result.RequeueIfError(err)
+	}
+
+	log.Info("tiflow cluster members are torn down, removing finalizer")
+	controllerutil.RemoveFinalizer(tc, label.TiflowClusterFinalizer)
+	if err := r.Update(ctx, tc); err != nil {
+		log.Error(err, "failed to remove finalizer from tiflow cluster")
+		return result.RequeueIfError(client.IgnoreNotFound(err))
+	}
+	metrics.DeleteClusterMetrics(tc.GetNamespace(), tc.GetName())
+
 	return result.NoRequeue()
 }
+
 func (r *TiflowClusterReconciler) updateTiflowClusterStatus(tc *pingcapcomv1alpha1.TiflowCluster) error {
 	return status.NewTiflowClusterStatusManager(r.Client, r.ClientSet, tc).Update()
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TiflowClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &pingcapcomv1alpha1.TiflowCluster{},
+		clusterRefIndexKey, indexClusterRef); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&pingcapcomv1alpha1.TiflowCluster{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
+		Owns(&networkingv1.Ingress{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		// heterogeneous clusters depend on the master of the referenced cluster
+		Watches(&source.Kind{Type: &pingcapcomv1alpha1.TiflowCluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.dependentClusters)).
+		// pods are watched for the operations requested by their annotations
+		Watches(&source.Kind{Type: &corev1.Pod{}},
+			handler.EnqueueRequestsFromMapFunc(r.operatedPodCluster)).
 		Complete(r)
 }
+
+// operatedPodCluster enqueues the TiflowCluster of the pod if an operation is requested or ongoing on the pod,
+// or the pod is preferred as the tiflow-master leader. The TiflowCluster is the owner of the pod's statefulSet.
+func (r *TiflowClusterReconciler) operatedPodCluster(obj client.Object) []reconcile.Request {
+	if obj.GetLabels()[label.ManagedByLabelKey] != label.TiFlowOperator {
+		return nil
+	}
+	ann := obj.GetAnnotations()
+	_, debug := ann[label.AnnDebugModeKey]
+	_, deleting := ann[label.AnnDeletePodKey]
+	_, evicting := ann[label.AnnEvictLeaderKey]
+	_, preferred := ann[label.AnnPreferredLeaderKey]
+	if !debug && !deleting && !evicting && !preferred && ann[label.AnnRunModeKey] != label.AnnRunModeDebug {
+		return nil
+	}
+
+	stsRef := metav1.GetControllerOf(obj)
+	if stsRef == nil || stsRef.Kind != "StatefulSet" {
+		return nil
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(context.Background(), types.NamespacedName{Namespace: obj.GetNamespace(), Name: stsRef.Name}, sts); err != nil {
+		r.Log.Error(err, "failed to get the statefulSet of operated pod", "Pod", clusterRefKey(obj.GetNamespace(), obj.GetName()))
+		return nil
+	}
+	tcRef := metav1.GetControllerOf(sts)
+	if tcRef == nil || tcRef.Kind != controller.ControllerKind.Kind {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: obj.GetNamespace(),
+			Name:      tcRef.Name,
+		},
+	}}
+}
+
+// indexClusterRef returns the index value of the cluster referenced by spec.cluster
+func indexClusterRef(obj client.Object) []string {
+	tc, ok := obj.(*pingcapcomv1alpha1.TiflowCluster)
+	if !ok || !tc.Heterogeneous() {
+		return nil
+	}
+	ns, name := tc.ReferencedCluster()
+	return []string{clusterRefKey(ns, name)}
+}
+
+func clusterRefKey(ns, name string) string {
+	return fmt.Sprintf("%s/%s", ns, name)
+}
+
+// dependentClusters enqueues all TiflowClusters referencing the changed one by spec.cluster
+func (r *TiflowClusterReconciler) dependentClusters(obj client.Object) []reconcile.Request {
+	tcList := &pingcapcomv1alpha1.TiflowClusterList{}
+	if err := r.List(context.Background(), tcList,
+		client.MatchingFields{clusterRefIndexKey: clusterRefKey(obj.GetNamespace(), obj.GetName())}); err != nil {
+		r.Log.Error(err, "failed to list dependent tiflow clusters", "TiflowCluster", clusterRefKey(obj.GetNamespace(), obj.GetName()))
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(tcList.Items))
+	for _, tc := range tcList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: tc.GetNamespace(),
+				Name:      tc.GetName(),
+			},
+		})
+	}
+	return requests
+}
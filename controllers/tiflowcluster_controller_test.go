@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+)
+
+// fakeControl tears down the tiflow cluster once it's asked for enough times
+type fakeControl struct {
+	deleting int
+}
+
+func (c *fakeControl) UpdateTiflowCluster(context.Context, *pingcapcomv1alpha1.TiflowCluster) error {
+	return nil
+}
+
+func (c *fakeControl) DeleteTiflowCluster(context.Context, *pingcapcomv1alpha1.TiflowCluster) error {
+	if c.deleting > 0 {
+		c.deleting--
+		return controller.RequeueErrorf("still deleting")
+	}
+	return nil
+}
+
+func TestReconcileDeletion(t *testing.T) {
+	ctx := context.Background()
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, pingcapcomv1alpha1.AddToScheme(s))
+
+	now := metav1.Now()
+	tc := &pingcapcomv1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{
+		Namespace:         "ns-1",
+		Name:              "demo",
+		DeletionTimestamp: &now,
+		Finalizers:        []string{label.TiflowClusterFinalizer},
+	}}
+	cli := fake.NewClientBuilder().WithScheme(s).WithObjects(tc).Build()
+	r := &TiflowClusterReconciler{
+		Client:  cli,
+		Log:     ctrl.Log.WithName("test"),
+		Scheme:  s,
+		Control: &fakeControl{deleting: 1},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ns-1", Name: "demo"}}
+
+	// the finalizer is kept until tearing down is done
+	res, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NotZero(t, res.RequeueAfter)
+	got := &pingcapcomv1alpha1.TiflowCluster{}
+	require.NoError(t, cli.Get(ctx, req.NamespacedName, got))
+	require.Contains(t, got.Finalizers, label.TiflowClusterFinalizer)
+
+	res, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	err = cli.Get(ctx, req.NamespacedName, got)
+	if !apierrors.IsNotFound(err) {
+		require.NoError(t, err)
+		require.NotContains(t, got.Finalizers, label.TiflowClusterFinalizer)
+	}
+}
+
+// indexedClient filters TiflowClusters by the cluster reference index, which the fake client doesn't support
+type indexedClient struct {
+	client.Client
+}
+
+func (c *indexedClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if err := c.Client.List(ctx, list, opts...); err != nil {
+		return err
+	}
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+	tcList, ok := list.(*pingcapcomv1alpha1.TiflowClusterList)
+	if !ok || listOpts.FieldSelector == nil {
+		return nil
+	}
+	items := tcList.Items[:0]
+	for i := range tcList.Items {
+		for _, v := range indexClusterRef(&tcList.Items[i]) {
+			if listOpts.FieldSelector.Matches(fields.Set{clusterRefIndexKey: v}) {
+				items = append(items, tcList.Items[i])
+			}
+		}
+	}
+	tcList.Items = items
+	return nil
+}
+
+func TestDependentClusters(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, pingcapcomv1alpha1.AddToScheme(s))
+
+	newCluster := func(ns, name string, ref *pingcapcomv1alpha1.ClusterRef) *pingcapcomv1alpha1.TiflowCluster {
+		return &pingcapcomv1alpha1.TiflowCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       pingcapcomv1alpha1.TiflowClusterSpec{Cluster: ref},
+		}
+	}
+	base := newCluster("ns-1", "base", nil)
+	// the namespace of the reference defaults to the one of the cluster
+	local := newCluster("ns-1", "local", &pingcapcomv1alpha1.ClusterRef{Name: "base"})
+	cross := newCluster("ns-2", "cross", &pingcapcomv1alpha1.ClusterRef{Namespace: "ns-1", Name: "base"})
+	other := newCluster("ns-2", "other", &pingcapcomv1alpha1.ClusterRef{Name: "base"})
+
+	require.Nil(t, indexClusterRef(base))
+	require.Equal(t, []string{"ns-1/base"}, indexClusterRef(local))
+	require.Equal(t, []string{"ns-1/base"}, indexClusterRef(cross))
+	require.Equal(t, []string{"ns-2/base"}, indexClusterRef(other))
+
+	r := &TiflowClusterReconciler{
+		Client: &indexedClient{fake.NewClientBuilder().WithScheme(s).WithObjects(base, local, cross, other).Build()},
+		Log:    ctrl.Log.WithName("test"),
+	}
+	require.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns-1", Name: "local"}},
+		{NamespacedName: types.NamespacedName{Namespace: "ns-2", Name: "cross"}},
+	}, r.dependentClusters(base))
+	require.Empty(t, r.dependentClusters(local))
+}
+
+func TestOperatedPodCluster(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, pingcapcomv1alpha1.AddToScheme(s))
+
+	tc := &pingcapcomv1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo", UID: "demo-uid"}}
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace:       "ns-1",
+		Name:            "demo-tiflow-executor",
+		OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+	}}
+	stsOwner := true
+	newPod := func(ann map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      "demo-tiflow-executor-0",
+			// the instance of a heterogeneous cluster is not its name
+			Labels:      label.New().Instance("base").TiflowExecutor().Labels(),
+			Annotations: ann,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       sts.Name,
+				Controller: &stsOwner,
+			}},
+		}}
+	}
+	r := &TiflowClusterReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithObjects(sts).Build(),
+		Log:    ctrl.Log.WithName("test"),
+	}
+
+	debugPod := newPod(map[string]string{label.AnnDebugModeKey: label.AnnDebugModeVal})
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns-1", Name: "demo"}}},
+		r.operatedPodCluster(debugPod))
+	require.Empty(t, r.operatedPodCluster(newPod(nil)))
+
+	// the statefulSet is not created by the operator
+	sts.OwnerReferences = nil
+	require.NoError(t, r.Update(context.Background(), sts))
+	require.Empty(t, r.operatedPodCluster(debugPod))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/monitor"
+	"github.com/pingcap/tiflow-operator/pkg/result"
+)
+
+// TiflowMonitorReconciler reconciles a TiflowMonitor object
+type TiflowMonitorReconciler struct {
+	client.Client
+	Log     logr.Logger
+	Scheme  *runtime.Scheme
+	Control monitor.ControlInterface
+}
+
+func NewTiflowMonitorReconciler(cli client.Client, scheme *runtime.Scheme) *TiflowMonitorReconciler {
+	return &TiflowMonitorReconciler{
+		Client:  cli,
+		Log:     ctrl.Log.WithName("controller").WithName("TiflowMonitor"),
+		Scheme:  scheme,
+		Control: monitor.NewDefaultTiflowMonitorControl(cli),
+	}
+}
+
+// +kubebuilder:rbac:groups=pingcap.com,resources=tiflowmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=pingcap.com,resources=tiflowmonitors/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=pingcap.com,resources=tiflowmonitors/finalizers,verbs=update
+
+// Reconcile deploys Prometheus and Grafana for the TiflowClusters referenced by the TiflowMonitor,
+// and refreshes the addresses of them in status.
+func (r *TiflowMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("TiflowMonitor", req.NamespacedName)
+
+	tm := &pingcapcomv1alpha1.TiflowMonitor{}
+	if err := r.Get(ctx, req.NamespacedName, tm); err != nil {
+		return result.RequeueIfError(client.IgnoreNotFound(err))
+	}
+
+	if !tm.GetDeletionTimestamp().IsZero() {
+		// the children are removed by the garbage collector
+		return result.NoRequeue()
+	}
+
+	oldStatus := tm.Status.DeepCopy()
+	if err := r.Control.UpdateTiflowMonitor(ctx, tm); err != nil {
+		log.Error(err, "failed to sync tiflow monitor")
+		return result.RequeueIfError(err)
+	}
+
+	if !apiequality.Semantic.DeepEqual(oldStatus, &tm.Status) {
+		if err := r.Status().Update(ctx, tm); err != nil {
+			log.Error(err, "failed to update tiflow monitor status")
+			return result.RequeueIfError(err)
+		}
+	}
+
+	if !tm.Status.Ready {
+		return result.RequeueAfter(result.ShortPauseTime, nil)
+	}
+	return result.NoRequeue()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TiflowMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&pingcapcomv1alpha1.TiflowMonitor{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		// scrape configs depend on the components and TLS setting of the referenced clusters
+		Watches(&source.Kind{Type: &pingcapcomv1alpha1.TiflowCluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.referencingMonitors)).
+		Complete(r)
+}
+
+// referencingMonitors enqueues all TiflowMonitors scraping the changed TiflowCluster
+func (r *TiflowMonitorReconciler) referencingMonitors(obj client.Object) []reconcile.Request {
+	tmList := &pingcapcomv1alpha1.TiflowMonitorList{}
+	if err := r.List(context.Background(), tmList); err != nil {
+		r.Log.Error(err, "failed to list tiflow monitors", "TiflowCluster", clusterRefKey(obj.GetNamespace(), obj.GetName()))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, tm := range tmList.Items {
+		for _, ref := range tm.ClusterRefs() {
+			if ref.Namespace == obj.GetNamespace() && ref.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: tm.GetNamespace(),
+						Name:      tm.GetName(),
+					},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
@@ -34,6 +34,12 @@ import (
 
 	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/controllers"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/discovery"
+	"github.com/pingcap/tiflow-operator/pkg/metrics"
+	"github.com/pingcap/tiflow-operator/pkg/prestop"
+	"github.com/pingcap/tiflow-operator/pkg/probe"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -50,6 +56,31 @@ func init() {
 }
 
 func main() {
+	// the image of operator also serves as the discovery service of tiflow clusters
+	if len(os.Args) > 1 && os.Args[1] == "discovery" {
+		if err := discovery.Run(os.Args[2:]); err != nil {
+			setupLog.Error(err, "problem running discovery")
+			os.Exit(1)
+		}
+		return
+	}
+	// and handles the preStop hooks of tiflow components
+	if len(os.Args) > 1 && os.Args[1] == "prestop" {
+		if err := prestop.Run(os.Args[2:]); err != nil {
+			setupLog.Error(err, "problem running prestop")
+			os.Exit(1)
+		}
+		return
+	}
+	// and probes tiflow components serving with mutual TLS
+	if len(os.Args) > 1 && os.Args[1] == "probe" {
+		if err := probe.Run(os.Args[2:]); err != nil {
+			setupLog.Error(err, "problem running probe")
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
@@ -60,6 +91,8 @@ func main() {
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&debug, "standalone-reconcile", false, "In the test, open the standalone reconcile")
+	flag.StringVar(&controller.DiscoveryImage, "discovery-image", controller.DiscoveryImage,
+		"The image of discovery service and preStop hooks, it should be the image of operator itself.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -67,6 +100,8 @@ func main() {
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	// requests to tiflow-master are only measured by the operator
+	tiflowapi.Observer = metrics.ObserveMasterAPI
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
@@ -98,12 +133,19 @@ func main() {
 		os.Exit(1)
 	}
 
-	reconciler := controllers.NewTiflowClusterReconciler(mgr.GetClient(), clientSet, mgr.GetScheme())
+	reconciler := controllers.NewTiflowClusterReconciler(mgr.GetClient(), clientSet, mgr.GetScheme(),
+		mgr.GetEventRecorderFor("tiflow-operator"))
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "TiflowCluster")
 		os.Exit(1)
 	}
 
+	monitorReconciler := controllers.NewTiflowMonitorReconciler(mgr.GetClient(), mgr.GetScheme())
+	if err = monitorReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "TiflowMonitor")
+		os.Exit(1)
+	}
+
 	if debug {
 		standaloneReconcile := controllers.NewStandaloneReconciler(mgr.GetClient(), mgr.GetScheme())
 		if err = standaloneReconcile.SetupWithManager(mgr); err != nil {
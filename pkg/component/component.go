@@ -37,6 +37,9 @@ type ComponentAccessor interface {
 	AdditionalVolumes() []corev1.Volume
 	AdditionalVolumeMounts() []corev1.VolumeMount
 	TerminationGracePeriodSeconds() *int64
+	ReadinessProbe() *corev1.Probe
+	LivenessProbe() *corev1.Probe
+	StartupProbe() *corev1.Probe
 	StatefulSetUpdateStrategy() apps.StatefulSetUpdateStrategyType
 	PodManagementPolicy() apps.PodManagementPolicyType
 	TopologySpreadConstraints() []corev1.TopologySpreadConstraint
@@ -297,6 +300,27 @@ func (a *componentAccessorImpl) TerminationGracePeriodSeconds() *int64 {
 	return a.ComponentSpec.TerminationGracePeriodSeconds
 }
 
+func (a *componentAccessorImpl) ReadinessProbe() *corev1.Probe {
+	if a.ComponentSpec == nil {
+		return nil
+	}
+	return a.ComponentSpec.ReadinessProbe
+}
+
+func (a *componentAccessorImpl) LivenessProbe() *corev1.Probe {
+	if a.ComponentSpec == nil {
+		return nil
+	}
+	return a.ComponentSpec.LivenessProbe
+}
+
+func (a *componentAccessorImpl) StartupProbe() *corev1.Probe {
+	if a.ComponentSpec == nil {
+		return nil
+	}
+	return a.ComponentSpec.StartupProbe
+}
+
 func (a *componentAccessorImpl) TopologySpreadConstraints() []corev1.TopologySpreadConstraint {
 	var tscs []corev1.TopologySpreadConstraint
 	if a.ComponentSpec != nil && len(a.ComponentSpec.TopologySpreadConstraints) > 0 {
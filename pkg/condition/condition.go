@@ -1,6 +1,7 @@
 package condition
 
 import (
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
@@ -8,78 +9,51 @@ import (
 
 func InitConditionsIfNeed(status *v1alpha1.TiflowClusterStatus, now metav1.Time) {
 	if status.ClusterConditions == nil {
-		status.ClusterConditions = []v1alpha1.TiflowClusterCondition{}
+		status.ClusterConditions = []metav1.Condition{}
 	}
 	return
 }
 
-func True(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) bool {
-	index := pos(ctype, conds)
-	if index == -1 || conds[index].Status == metav1.ConditionUnknown {
-		return false
-	}
-
-	return conds[index].Status == metav1.ConditionTrue
+func True(ctype v1alpha1.TiflowClusterConditionType, conds []metav1.Condition) bool {
+	return meta.IsStatusConditionTrue(conds, string(ctype))
 }
 
-func False(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) bool {
-	index := pos(ctype, conds)
-	if index == -1 || conds[index].Status == metav1.ConditionUnknown {
-		return false
-	}
-
-	return conds[index].Status == metav1.ConditionFalse
+func False(ctype v1alpha1.TiflowClusterConditionType, conds []metav1.Condition) bool {
+	return meta.IsStatusConditionFalse(conds, string(ctype))
 }
 
-func Unknown(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) bool {
-	index := pos(ctype, conds)
-	if index == -1 {
-		return false
-	}
-
-	return conds[index].Status == metav1.ConditionUnknown
+func Unknown(ctype v1alpha1.TiflowClusterConditionType, conds []metav1.Condition) bool {
+	return meta.IsStatusConditionPresentAndEqual(conds, string(ctype), metav1.ConditionUnknown)
 }
 
 func SetFalse(ctype v1alpha1.TiflowClusterConditionType, status *v1alpha1.TiflowClusterStatus, now metav1.Time) {
-	setConditionStatus(ctype, metav1.ConditionFalse, status, now)
+	setConditionStatus(ctype, metav1.ConditionFalse, status, v1alpha1.ConditionUnsatisfiedReason, "", now)
 }
 
 func SetTrue(ctype v1alpha1.TiflowClusterConditionType, status *v1alpha1.TiflowClusterStatus, now metav1.Time) {
-	setConditionStatus(ctype, metav1.ConditionTrue, status, now)
+	setConditionStatus(ctype, metav1.ConditionTrue, status, v1alpha1.ConditionSatisfiedReason, "", now)
 }
 
-func setConditionStatus(ctype v1alpha1.TiflowClusterConditionType, status metav1.ConditionStatus, clusterStatus *v1alpha1.TiflowClusterStatus, now metav1.Time) {
-	cond := findOrCreate(ctype, clusterStatus)
-
-	if cond.Status == status {
-		return
-	}
-
-	cond.Status = status
-	cond.LastTransitionTime = now
+// SetFalseWithReason sets the condition to false, and records the reason with a human readable message
+func SetFalseWithReason(ctype v1alpha1.TiflowClusterConditionType, status *v1alpha1.TiflowClusterStatus, reason, message string, now metav1.Time) {
+	setConditionStatus(ctype, metav1.ConditionFalse, status, reason, message, now)
 }
 
-func findOrCreate(ctype v1alpha1.TiflowClusterConditionType, clusterStatus *v1alpha1.TiflowClusterStatus) *v1alpha1.TiflowClusterCondition {
-	index := pos(ctype, clusterStatus.ClusterConditions)
-	if index >= 0 {
-		return &clusterStatus.ClusterConditions[index]
-	}
-
-	clusterStatus.ClusterConditions = append(clusterStatus.ClusterConditions, v1alpha1.TiflowClusterCondition{
-		Type:               ctype,
-		Status:             metav1.ConditionUnknown,
-		LastTransitionTime: metav1.Now(),
-	})
-
-	return &clusterStatus.ClusterConditions[len(clusterStatus.ClusterConditions)-1]
+// SetTrueWithReason sets the condition to true, and records the reason with a human readable message
+func SetTrueWithReason(ctype v1alpha1.TiflowClusterConditionType, status *v1alpha1.TiflowClusterStatus, reason, message string, now metav1.Time) {
+	setConditionStatus(ctype, metav1.ConditionTrue, status, reason, message, now)
 }
 
-func pos(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) int {
-	for i := range conds {
-		if conds[i].Type == ctype {
-			return i
-		}
-	}
-
-	return -1
+// setConditionStatus sets the condition observed for status.observedGeneration,
+// the transition time is only updated when the status of the condition changes.
+func setConditionStatus(ctype v1alpha1.TiflowClusterConditionType, status metav1.ConditionStatus, clusterStatus *v1alpha1.TiflowClusterStatus,
+	reason, message string, now metav1.Time) {
+	meta.SetStatusCondition(&clusterStatus.ClusterConditions, metav1.Condition{
+		Type:               string(ctype),
+		Status:             status,
+		ObservedGeneration: clusterStatus.ObservedGeneration,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	})
 }
@@ -0,0 +1,91 @@
+package condition
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+func TestSetConditionStatus(t *testing.T) {
+	status := &v1alpha1.TiflowClusterStatus{ObservedGeneration: 1}
+	InitConditionsIfNeed(status, metav1.Now())
+
+	first := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	SetFalseWithReason(v1alpha1.MasterMembersChecked, status, "QuorumUnhealthy", "2 of 3 members are unhealthy", first)
+	require.True(t, False(v1alpha1.MasterMembersChecked, status.ClusterConditions))
+
+	// the transition time is kept while the status doesn't change
+	status.ObservedGeneration = 2
+	SetFalse(v1alpha1.MasterMembersChecked, status, metav1.Now())
+	cond := meta.FindStatusCondition(status.ClusterConditions, string(v1alpha1.MasterMembersChecked))
+	require.Equal(t, first, cond.LastTransitionTime)
+	require.Equal(t, v1alpha1.ConditionUnsatisfiedReason, cond.Reason)
+	require.Empty(t, cond.Message)
+	require.Equal(t, int64(2), cond.ObservedGeneration)
+
+	SetTrue(v1alpha1.MasterMembersChecked, status, metav1.Now())
+	cond = meta.FindStatusCondition(status.ClusterConditions, string(v1alpha1.MasterMembersChecked))
+	require.True(t, True(v1alpha1.MasterMembersChecked, status.ClusterConditions))
+	require.True(t, cond.LastTransitionTime.After(first.Time))
+	require.Equal(t, v1alpha1.ConditionSatisfiedReason, cond.Reason)
+	require.Len(t, status.ClusterConditions, 1)
+	require.False(t, Unknown(v1alpha1.MasterSyncChecked, status.ClusterConditions))
+}
+
+func TestApplyWhileReconcilePaused(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns-1",
+		Name:        "demo",
+		Annotations: map[string]string{label.AnnPauseReconcileKey: label.AnnPauseReconcileVal},
+	}}
+	tcm := &TiflowClusterConditionManager{TiflowCluster: tc}
+
+	require.NoError(t, tcm.Apply())
+	cond := meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.MasterSyncChecked))
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, v1alpha1.ReconcilePausedReason, cond.Reason)
+	require.True(t, False(v1alpha1.ExecutorSyncChecked, tc.GetClusterConditions()))
+
+	delete(tc.Annotations, label.AnnPauseReconcileKey)
+	require.NoError(t, tcm.Apply())
+	require.True(t, True(v1alpha1.MasterSyncChecked, tc.GetClusterConditions()))
+	require.True(t, True(v1alpha1.ExecutorSyncChecked, tc.GetClusterConditions()))
+}
+
+func TestProbeMembers(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer slow.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"advertise_addr":"demo-tiflow-master-0"}`))
+	}))
+	defer healthy.Close()
+
+	mcm := &masterConditionManager{TiflowCluster: &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+	}}
+	host := func(ts *httptest.Server) string { return strings.TrimPrefix(ts.URL, "http://") }
+	masters := []*tiflowapi.Master{
+		{Name: "demo-tiflow-master-0", Address: host(healthy)},
+		{Name: "demo-tiflow-master-1", Address: host(slow)},
+		{Name: "demo-tiflow-master-2", Address: host(slow)},
+		{Name: "demo-tiflow-master-3"},
+	}
+
+	// the slow members are probed at the same time
+	start := time.Now()
+	require.Equal(t, []bool{true, false, false, false}, mcm.probeMembers(masters))
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -8,6 +8,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
 	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 )
 
@@ -46,8 +47,26 @@ func (tcm *TiflowClusterConditionManager) Apply() error {
 		tcm.GetNamespace(), tcm.GetName(), "", tcm.IsClusterTLSEnabled()).
 		GetURL()
 
-	SetTrue(v1alpha1.MasterSyncChecked, tcm.GetClusterStatus(), metav1.Now())
-	SetTrue(v1alpha1.ExecutorSyncChecked, tcm.GetClusterStatus(), metav1.Now())
-	SetTrue(v1alpha1.VersionChecked, tcm.GetClusterStatus(), metav1.Now())
+	if tcm.ReconcilePaused() {
+		// nothing is synced while reconciling is paused, only the observed status is refreshed
+		message := "reconciling is paused by annotation, the cluster may not match the spec"
+		SetFalseWithReason(v1alpha1.MasterSyncChecked, tcm.GetClusterStatus(), v1alpha1.ReconcilePausedReason, message, metav1.Now())
+		SetFalseWithReason(v1alpha1.ExecutorSyncChecked, tcm.GetClusterStatus(), v1alpha1.ReconcilePausedReason, message, metav1.Now())
+	} else {
+		SetTrue(v1alpha1.MasterSyncChecked, tcm.GetClusterStatus(), metav1.Now())
+		SetTrue(v1alpha1.ExecutorSyncChecked, tcm.GetClusterStatus(), metav1.Now())
+	}
+	tcm.versionVerify()
 	return nil
 }
+
+// versionVerify reports the first component whose desired version can't be rolled out
+func (tcm *TiflowClusterConditionManager) versionVerify() {
+	for _, memberType := range []v1alpha1.MemberType{v1alpha1.TiFlowMasterMemberType, v1alpha1.TiFlowExecutorMemberType} {
+		if reason, message := mngerutils.CheckVersion(tcm.TiflowCluster, memberType); reason != "" {
+			SetFalseWithReason(v1alpha1.VersionChecked, tcm.GetClusterStatus(), reason, message, metav1.Now())
+			return
+		}
+	}
+	SetTrue(v1alpha1.VersionChecked, tcm.GetClusterStatus(), metav1.Now())
+}
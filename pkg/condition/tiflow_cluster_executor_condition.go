@@ -164,11 +164,10 @@ func (ecm *executorConditionManager) syncMembersStatus(ctx context.Context, sts
 	tcName := ecm.GetName()
 
 	if ecm.Heterogeneous() && ecm.WithoutLocalMaster() {
-		ns = ecm.Spec.Cluster.Namespace
-		tcName = ecm.Spec.Cluster.Name
+		ns, tcName = ecm.ReferencedCluster()
 	}
 
-	tiflowClient := tiflowapi.GetMasterClient(ecm.cli, ns, tcName, "", ecm.IsClusterTLSEnabled())
+	tiflowClient := tiflowapi.GetJoinedMasterClient(ecm.cli, ecm.TiflowCluster)
 
 	// get executors info from master
 	executorsInfo, err := tiflowClient.GetExecutors()
@@ -264,15 +263,7 @@ func (ecm *executorConditionManager) readyVerify() bool {
 }
 
 func (ecm *executorConditionManager) leaderVerify() bool {
-	ns := ecm.GetNamespace()
-	tcName := ecm.GetName()
-
-	if ecm.Heterogeneous() && ecm.WithoutLocalMaster() {
-		ns = ecm.Spec.Cluster.Namespace
-		tcName = ecm.Spec.Cluster.Name
-	}
-
-	tiflowClient := tiflowapi.GetMasterClient(ecm.cli, ns, tcName, "", ecm.IsClusterTLSEnabled())
+	tiflowClient := tiflowapi.GetJoinedMasterClient(ecm.cli, ecm.TiflowCluster)
 	leader, err := tiflowClient.GetLeader()
 	if err != nil {
 		return false
@@ -3,6 +3,8 @@ package condition
 import (
 	"context"
 	"fmt"
+	"sync"
+
 	"github.com/pingcap/tiflow-operator/pkg/status"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -12,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/metrics"
 	"github.com/pingcap/tiflow-operator/pkg/result"
 
 	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
@@ -66,6 +69,7 @@ func (mcm *masterConditionManager) Verify(ctx context.Context) error {
 		SetTrue(v1alpha1.MasterReplicaChecked, mcm.GetClusterStatus(), metav1.Now())
 	} else {
 		SetFalse(v1alpha1.MasterReplicaChecked, mcm.GetClusterStatus(), metav1.Now())
+		mcm.refreshMembers(ctx, sts)
 		return result.NotReadyErr{
 			Err: fmt.Errorf("master [%s/%s] verify: actual is not equal to desired replicas ", ns, tcName),
 		}
@@ -75,6 +79,7 @@ func (mcm *masterConditionManager) Verify(ctx context.Context) error {
 		SetTrue(v1alpha1.MasterReadyChecked, mcm.GetClusterStatus(), metav1.Now())
 	} else {
 		SetFalse(v1alpha1.MasterReadyChecked, mcm.GetClusterStatus(), metav1.Now())
+		mcm.refreshMembers(ctx, sts)
 		return result.NotReadyErr{
 			Err: fmt.Errorf("master [%s/%s] verify: cluster are not reday", ns, tcName),
 		}
@@ -95,10 +100,20 @@ func (mcm *masterConditionManager) Verify(ctx context.Context) error {
 	}
 	SetTrue(v1alpha1.MastersInfoUpdatedChecked, mcm.GetClusterStatus(), metav1.Now())
 
+	if healthy, total := mcm.membersHealth(); total > 0 && healthy*2 <= total {
+		msg := fmt.Sprintf("%d of %d tiflow-master members are healthy, quorum is lost", healthy, total)
+		SetFalseWithReason(v1alpha1.MasterMembersChecked, mcm.GetClusterStatus(), "QuorumUnhealthy", msg, metav1.Now())
+		return result.SyncStatusErr{
+			Err: fmt.Errorf("master [%s/%s] verify: %s", ns, tcName, msg),
+		}
+	}
+
 	// todo: unclear behavior of peerMembers
 	if mcm.MasterStsDesiredReplicas() == mcm.MasterActualMembers() {
 		SetTrue(v1alpha1.MasterMembersChecked, mcm.GetClusterStatus(), metav1.Now())
 	} else {
+		SetFalseWithReason(v1alpha1.MasterMembersChecked, mcm.GetClusterStatus(), "MembersIncomplete",
+			fmt.Sprintf("%d of %d tiflow-master members are registered", mcm.MasterActualMembers(), mcm.MasterStsDesiredReplicas()), metav1.Now())
 		return result.SyncStatusErr{
 			Err: fmt.Errorf("master [%s/%s] verify: member infos is incomplete", ns, tcName),
 		}
@@ -131,6 +146,14 @@ func (mcm *masterConditionManager) Update(ctx context.Context, sts *appsv1.State
 	return nil
 }
 
+// refreshMembers updates the members and their health while scaling or upgrading,
+// as the scaler and the upgrader gate on them. The error is ignored since the cluster is not ready anyway.
+func (mcm *masterConditionManager) refreshMembers(ctx context.Context, sts *appsv1.StatefulSet) {
+	if err := mcm.update(ctx, sts); err != nil {
+		klog.Infof("tiflow cluster [%s/%s] failed to refresh tiflow-master members: %v", mcm.GetNamespace(), mcm.GetName(), err)
+	}
+}
+
 func (mcm *masterConditionManager) update(ctx context.Context, sts *appsv1.StatefulSet) error {
 	ns := mcm.GetNamespace()
 	tcName := mcm.GetName()
@@ -156,7 +179,7 @@ func (mcm *masterConditionManager) update(ctx context.Context, sts *appsv1.State
 		}
 
 		// tiflow-master service has no endpoints
-		if eps != nil && eps.Items != nil && len(eps.Items[0].Subsets) == 0 {
+		if eps != nil && len(eps.Items) > 0 && len(eps.Items[0].Subsets) == 0 {
 			return fmt.Errorf("%s, service %s/%s has no endpoints", err, ns, masterMemberName(tcName))
 		}
 
@@ -181,17 +204,22 @@ func (mcm *masterConditionManager) update(ctx context.Context, sts *appsv1.State
 func (mcm *masterConditionManager) updateMembersInfo(mastersInfo tiflowapi.MastersInfo) error {
 	ns := mcm.GetNamespace()
 
-	// TODO: WIP, need to get the information of memberDeleted and LastTransitionTime
+	// TODO: WIP, need to get the information of memberDeleted
 	members := make(map[string]v1alpha1.MasterMember)
 	peerMembers := make(map[string]v1alpha1.MasterMember)
-	for _, m := range mastersInfo.Masters {
+	health := mcm.probeMembers(mastersInfo.Masters)
+	for i, m := range mastersInfo.Masters {
 		member := v1alpha1.MasterMember{
 			Id:                 m.ID,
 			Address:            m.Address,
 			IsLeader:           m.IsLeader,
 			Name:               m.Name,
+			Health:             health[i],
 			LastTransitionTime: metav1.Now(),
 		}
+		if last, ok := mcm.lastMember(m.Name); ok && last.Health == member.Health && !last.LastTransitionTime.IsZero() {
+			member.LastTransitionTime = last.LastTransitionTime
+		}
 		clusterName, ordinal, namespace, err2 := getOrdinalFromName(m.Name, v1alpha1.TiFlowMasterMemberType)
 		if err2 == nil && clusterName == mcm.GetName() && namespace == ns && ordinal < mcm.MasterStsDesiredReplicas() {
 			members[m.Name] = member
@@ -205,6 +233,57 @@ func (mcm *masterConditionManager) updateMembersInfo(mastersInfo tiflowapi.Maste
 	return nil
 }
 
+// probeMembers probes the members concurrently, so that the dead ones don't add up their timeouts
+func (mcm *masterConditionManager) probeMembers(masters []*tiflowapi.Master) []bool {
+	health := make([]bool, len(masters))
+	var wg sync.WaitGroup
+	for i, m := range masters {
+		wg.Add(1)
+		go func(i int, m *tiflowapi.Master) {
+			defer wg.Done()
+			health[i] = mcm.probeMember(m)
+		}(i, m)
+	}
+	wg.Wait()
+	return health
+}
+
+// probeMember returns whether the member serves the API and sees the leader of tiflow-master cluster
+func (mcm *masterConditionManager) probeMember(m *tiflowapi.Master) bool {
+	if m.Address == "" {
+		return false
+	}
+	client := tiflowapi.GetMasterMemberClient(mcm.cli, mcm.GetNamespace(), mcm.GetName(), m.Address, mcm.IsClusterTLSEnabled())
+	if _, err := client.GetLeader(); err != nil {
+		klog.Infof("tiflow cluster [%s/%s]'s tiflow-master member %s is unhealthy: %v", mcm.GetNamespace(), mcm.GetName(), m.Name, err)
+		return false
+	}
+	return true
+}
+
+// lastMember returns the member recorded in status before this update
+func (mcm *masterConditionManager) lastMember(name string) (v1alpha1.MasterMember, bool) {
+	if m, ok := mcm.Status.Master.Members[name]; ok {
+		return m, true
+	}
+	m, ok := mcm.Status.Master.PeerMembers[name]
+	return m, ok
+}
+
+// membersHealth returns the number of healthy members and all the members, including the peer members
+func (mcm *masterConditionManager) membersHealth() (int, int) {
+	healthy, total := 0, 0
+	for _, members := range []map[string]v1alpha1.MasterMember{mcm.Status.Master.Members, mcm.Status.Master.PeerMembers} {
+		for _, m := range members {
+			total++
+			if m.Health {
+				healthy++
+			}
+		}
+	}
+	return healthy, total
+}
+
 func (mcm *masterConditionManager) versionVerify() bool {
 	klog.Infof("Master: CurrentRevision: %s , UpdateRevision: %s",
 		mcm.GetMasterStatus().StatefulSet.CurrentRevision,
@@ -247,6 +326,10 @@ func (mcm *masterConditionManager) leaderVerify() bool {
 		return false
 	}
 
+	if last := mcm.Status.Master.Leader.ClientURL; last != "" && last != leader.AdvertiseAddr {
+		klog.Infof("tiflow cluster [%s/%s]'s tiflow-master leader changed from %s to %s", ns, tcName, last, leader.AdvertiseAddr)
+		metrics.MasterLeaderChanges.WithLabelValues(ns, tcName).Inc()
+	}
 	mcm.Status.Master.Leader = v1alpha1.MasterMember{
 		ClientURL:          leader.AdvertiseAddr,
 		LastTransitionTime: metav1.Now(),
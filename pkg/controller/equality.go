@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/klog/v2"
 )
@@ -51,3 +52,86 @@ func SetServiceLastAppliedConfigAnnotation(svc *corev1.Service) error {
 	svc.Annotations[LastAppliedConfigAnnotation] = string(b)
 	return nil
 }
+
+// ingressLastAppliedConfig is the last applied config of Ingress, the annotations are recorded along with
+// the spec so that the annotations removed from the TiflowCluster can be told apart from the ones added by others
+type ingressLastAppliedConfig struct {
+	Spec        networkingv1.IngressSpec `json:"spec"`
+	Annotations map[string]string        `json:"annotations,omitempty"`
+}
+
+func getIngressLastAppliedConfig(ing *networkingv1.Ingress) (*ingressLastAppliedConfig, bool, error) {
+	lastAppliedConfig, ok := ing.Annotations[LastAppliedConfigAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+	applied := &ingressLastAppliedConfig{}
+	if err := json.Unmarshal([]byte(lastAppliedConfig), applied); err != nil {
+		klog.Errorf("unmarshal IngressSpec: [%s/%s]'s applied config failed,error: %v", ing.GetNamespace(), ing.GetName(), err)
+		return nil, false, err
+	}
+	return applied, true, nil
+}
+
+// IngressEqual compares the new Ingress's spec and annotations with old Ingress's last applied config,
+// the annotations of new Ingress are expected to be kept in the old one as well
+func IngressEqual(newIngress, oldIngress *networkingv1.Ingress) (bool, error) {
+	applied, ok, err := getIngressLastAppliedConfig(oldIngress)
+	if err != nil || !ok {
+		return false, err
+	}
+	newAnnotations := appliedIngressAnnotations(newIngress)
+	if len(newAnnotations) != len(applied.Annotations) {
+		return false, nil
+	}
+	for k, v := range newAnnotations {
+		if applied.Annotations[k] != v || oldIngress.Annotations[k] != v {
+			return false, nil
+		}
+	}
+	return apiequality.Semantic.DeepEqual(applied.Spec, newIngress.Spec), nil
+}
+
+// MergeIngressAnnotations returns the annotations of new Ingress along with the ones of old Ingress
+// which are not applied by the operator, the annotations applied last time but removed now are dropped
+func MergeIngressAnnotations(newIngress, oldIngress *networkingv1.Ingress) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range oldIngress.Annotations {
+		annotations[k] = v
+	}
+	if applied, ok, err := getIngressLastAppliedConfig(oldIngress); err == nil && ok {
+		for k := range applied.Annotations {
+			delete(annotations, k)
+		}
+	}
+	for k, v := range newIngress.Annotations {
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// SetIngressLastAppliedConfigAnnotation set last applied config info to Ingress's annotation
+func SetIngressLastAppliedConfigAnnotation(ing *networkingv1.Ingress) error {
+	b, err := json.Marshal(ingressLastAppliedConfig{
+		Spec:        ing.Spec,
+		Annotations: appliedIngressAnnotations(ing),
+	})
+	if err != nil {
+		return err
+	}
+	if ing.Annotations == nil {
+		ing.Annotations = map[string]string{}
+	}
+	ing.Annotations[LastAppliedConfigAnnotation] = string(b)
+	return nil
+}
+
+func appliedIngressAnnotations(ing *networkingv1.Ingress) map[string]string {
+	annotations := map[string]string{}
+	for k, v := range ing.Annotations {
+		if k != LastAppliedConfigAnnotation {
+			annotations[k] = v
+		}
+	}
+	return annotations
+}
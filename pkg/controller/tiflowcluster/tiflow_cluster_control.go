@@ -2,14 +2,28 @@ package tiflowcluster
 
 import (
 	"context"
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/pingcap/tiflow-operator/api/label"
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	"github.com/pingcap/tiflow-operator/pkg/manager"
 	"github.com/pingcap/tiflow-operator/pkg/manager/member"
+	"github.com/pingcap/tiflow-operator/pkg/manager/member/prune"
+	"github.com/pingcap/tiflow-operator/pkg/status"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 )
 
 // ControlInterface implements the control logic for updating TiflowClusters and their children StatefulSets.
@@ -18,25 +32,40 @@ import (
 type ControlInterface interface {
 	// UpdateTiflowCluster implements the control logic for StatefulSet creation, update, and deletion
 	UpdateTiflowCluster(ctx context.Context, cluster *v1alpha1.TiflowCluster) error
+	// DeleteTiflowCluster implements the control logic for tearing down a TiflowCluster which is being deleted
+	DeleteTiflowCluster(ctx context.Context, cluster *v1alpha1.TiflowCluster) error
 }
 
 // NewDefaultTiflowClusterControl returns a new instance of the default implementation tiflowClusterControlInterface that
 // implements the documented semantics for tiflowClusters.
-func NewDefaultTiflowClusterControl(cli client.Client, clientSet kubernetes.Interface) ControlInterface {
+func NewDefaultTiflowClusterControl(cli client.Client, clientSet kubernetes.Interface, recorder record.EventRecorder) ControlInterface {
 	return &defaultTiflowClusterControl{
 		cli:                   cli,
 		clientSet:             clientSet,
-		masterMemberManager:   member.NewMasterMemberManager(cli, clientSet),
-		executorMemberManager: member.NewExecutorMemberManager(cli, clientSet),
+		recorder:              recorder,
+		masterMemberManager:   member.NewMasterMemberManager(cli, clientSet, recorder),
+		executorMemberManager: member.NewExecutorMemberManager(cli, clientSet, recorder),
+		discoveryManager:      member.NewDiscoveryManager(cli),
+		monitorManager:        member.NewServiceMonitorManager(cli),
+		leaderManager:         member.NewPreferredLeaderManager(cli, recorder),
+		pvcPruner:             prune.NewPersistentVolumePruner(clientSet, recorder),
+		getMasterClient:       tiflowapi.GetMasterClient,
 	}
 }
 
 type defaultTiflowClusterControl struct {
 	cli                   client.Client
 	clientSet             kubernetes.Interface
+	recorder              record.EventRecorder
 	masterMemberManager   manager.TiflowManager
 	executorMemberManager manager.TiflowManager
+	discoveryManager      manager.TiflowManager
+	monitorManager        manager.TiflowManager
+	leaderManager         manager.TiflowManager
+	pvcPruner             prune.PVCPruner
 	conditionUpdater      condition.Condition
+	// getMasterClient provides the client of tiflow-master to drain jobs, see tiflowapi.GetMasterClient
+	getMasterClient func(cli client.Client, namespace, tcName, podName string, tlsEnabled bool) tiflowapi.MasterClient
 }
 
 // UpdateTiflowCluster executes the core logic loop for a tiflowcluster.
@@ -49,6 +78,25 @@ func (c *defaultTiflowClusterControl) UpdateTiflowCluster(ctx context.Context, t
 	c.conditionUpdater = condition.NewTiflowClusterConditionManager(c.cli, c.clientSet, tc)
 
 	oldStatus := tc.Status.DeepCopy()
+	// failures are only recorded in status by members, make them visible as events
+	defer event.RecordFailedSyncTypes(c.recorder, tc, oldStatus)
+
+	if tc.ReconcilePaused() {
+		// all mutations are skipped for break-glass operations, only status is refreshed
+		condition.SetTrueWithReason(v1alpha1.ReconcilePaused, tc.GetClusterStatus(), v1alpha1.ReconcilePausedReason,
+			"reconciling is paused by annotation, only status is refreshed", metav1.Now())
+		return c.conditionUpdater.Sync(ctx)
+	}
+	// the spec of this generation is acted on from now, conditions set below are observed for it
+	tc.Status.ObservedGeneration = tc.Generation
+	if condition.True(v1alpha1.ReconcilePaused, tc.GetClusterConditions()) {
+		condition.SetFalse(v1alpha1.ReconcilePaused, tc.GetClusterStatus(), metav1.Now())
+	}
+
+	if tc.IsPaused() {
+		// members are scaled to zero, there is nothing to verify
+		return c.suspendTiflowCluster(ctx, tc)
+	}
 
 	if err := c.updateTiflowCluster(ctx, tc); err != nil {
 		return err
@@ -58,6 +106,12 @@ func (c *defaultTiflowClusterControl) UpdateTiflowCluster(ctx context.Context, t
 		return err
 	}
 
+	// works that should be done after the members and conditions are synced:
+	//   - move the tiflow-master leader to the preferred members, requeued until a preferred member is elected
+	if err := c.leaderManager.Sync(ctx, tc); err != nil {
+		return err
+	}
+
 	// todo: need to modify
 	if apiequality.Semantic.DeepEqual(&tc.Status, oldStatus) {
 		return nil
@@ -67,6 +121,20 @@ func (c *defaultTiflowClusterControl) UpdateTiflowCluster(ctx context.Context, t
 }
 
 func (c *defaultTiflowClusterControl) updateTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	// works that should be done to make the discovery service current state match the desired state:
+	//   - create or update the discovery service
+	//   - create or update the discovery deployment, which tells tiflow-executors the alive tiflow-master members
+	if err := c.discoveryManager.Sync(ctx, tc); err != nil {
+		return err
+	}
+
+	// works that should be done to make the ServiceMonitors current state match the desired state:
+	//   - create or update ServiceMonitors of tiflow-master and tiflow-executor if monitoring is enabled
+	//   - delete them if monitoring is disabled, nothing is done if Prometheus Operator is not installed
+	if err := c.monitorManager.Sync(ctx, tc); err != nil {
+		return err
+	}
+
 	// works that should be done to make the tiflow-master cluster current state match the desired state:
 	//   - create or update the tiflow-master service
 	//   - create or update the tiflow-master headless service
@@ -96,3 +164,104 @@ func (c *defaultTiflowClusterControl) updateTiflowCluster(ctx context.Context, t
 
 	return nil
 }
+
+func (c *defaultTiflowClusterControl) suspendTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	// tiflow-executor should be suspended before tiflow-master, so that executors can leave the cluster gracefully.
+	// ConfigMaps, Services and PVCs are kept for resuming.
+	if err := c.executorMemberManager.Sync(ctx, tc); err != nil {
+		return err
+	}
+
+	return c.masterMemberManager.Sync(ctx, tc)
+}
+
+// DeleteTiflowCluster tears down a tiflowcluster in order before its finalizer is removed.
+func (c *defaultTiflowClusterControl) DeleteTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	oldStatus := tc.Status.DeepCopy()
+	defer event.RecordFailedSyncTypes(c.recorder, tc, oldStatus)
+
+	// works that should be done before the tiflowCluster object is removed:
+	//   - cancel or wait for all running jobs, refuse to go on unless force delete is annotated
+	//   - scale tiflow-executor to zero and wait for them to deregister from tiflow-master
+	//   - scale tiflow-master to zero
+	//   - delete or retain the PVCs according to PVReclaimPolicy
+	if err := c.drainJobs(ctx, tc); err != nil {
+		return err
+	}
+
+	if err := c.executorMemberManager.Delete(ctx, tc); err != nil {
+		return err
+	}
+
+	if err := c.masterMemberManager.Delete(ctx, tc); err != nil {
+		return err
+	}
+
+	return c.pvcPruner.Reclaim(ctx, tc)
+}
+
+// drainJobs makes sure there are no running jobs in the tiflow cluster before tearing it down.
+// Running jobs will be canceled only if the tiflowCluster is annotated with force delete.
+func (c *defaultTiflowClusterControl) drainJobs(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	// jobs belong to the remote tiflow cluster, leave them alone
+	if tc.Spec.Master == nil {
+		return nil
+	}
+
+	sts := &apps.StatefulSet{}
+	err := c.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowMasterMemberName(tcName),
+	}, sts)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("drainJobs: failed to get sts %s for cluster %s/%s, error: %v",
+			controller.TiflowMasterMemberName(tcName), ns, tcName, err)
+	}
+	// no master could serve, there is no way to drain jobs.
+	if sts.Status.ReadyReplicas == 0 {
+		return nil
+	}
+
+	masterClient := c.getMasterClient(c.cli, ns, tcName, "", tc.IsClusterTLSEnabled())
+	jobsInfo, err := masterClient.GetJobs()
+	if err != nil {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s] can not get jobs from tiflow-master, error: %v", ns, tcName, err)
+	}
+
+	running := make([]*tiflowapi.Job, 0, len(jobsInfo.Jobs))
+	for _, job := range jobsInfo.Jobs {
+		if !job.IsTerminated() {
+			running = append(running, job)
+		}
+	}
+	if len(running) == 0 {
+		return nil
+	}
+
+	if !member.NeedForceDelete(tc.GetAnnotations()) {
+		msg := fmt.Sprintf("tiflow cluster [%s/%s] still has %d running jobs, cancel them or annotate %s=%s to force delete",
+			ns, tcName, len(running), label.AnnForceDeleteKey, label.AnnForceDeleteVal)
+		status.Ongoing(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, msg)
+		return controller.RequeueErrorf("%s", msg)
+	}
+
+	for _, job := range running {
+		if job.State == tiflowapi.JobStateCanceling {
+			continue
+		}
+		klog.Infof("tiflow cluster: [%s/%s] is force deleting, cancel job %s", ns, tcName, job.ID)
+		if err := masterClient.CancelJob(job.ID); err != nil {
+			return controller.RequeueErrorf("tiflow cluster: [%s/%s] failed to cancel job %s, error: %v", ns, tcName, job.ID, err)
+		}
+	}
+
+	status.Ongoing(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
+		fmt.Sprintf("tiflow cluster [%s/%s] is canceling %d jobs before deleting", ns, tcName, len(running)))
+	return controller.RequeueErrorf("tiflow cluster: [%s/%s] is waiting for %d jobs to be canceled", ns, tcName, len(running))
+}
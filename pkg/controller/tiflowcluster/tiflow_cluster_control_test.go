@@ -0,0 +1,114 @@
+package tiflowcluster
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+// fakeTeardown records the steps of tearing down a tiflow cluster
+type fakeTeardown struct {
+	steps []string
+}
+
+type fakeMemberManager struct {
+	name     string
+	teardown *fakeTeardown
+}
+
+func (m *fakeMemberManager) Sync(context.Context, *v1alpha1.TiflowCluster) error {
+	return nil
+}
+
+func (m *fakeMemberManager) Delete(context.Context, *v1alpha1.TiflowCluster) error {
+	m.teardown.steps = append(m.teardown.steps, m.name)
+	return nil
+}
+
+type fakePVCPruner struct {
+	teardown *fakeTeardown
+}
+
+func (p *fakePVCPruner) Prune(context.Context, *v1alpha1.TiflowCluster) error {
+	return nil
+}
+
+func (p *fakePVCPruner) Reclaim(context.Context, *v1alpha1.TiflowCluster) error {
+	p.teardown.steps = append(p.teardown.steps, "reclaim")
+	return nil
+}
+
+func TestDeleteTiflowCluster(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master:   &v1alpha1.MasterSpec{},
+			Executor: &v1alpha1.ExecutorSpec{},
+		},
+	}
+	sts := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: controller.TiflowMasterMemberName("demo")},
+		Status:     apps.StatefulSetStatus{ReadyReplicas: 3},
+	}
+
+	jobs := `{"jobs":[{"id":"job-1","state":"Running"},{"id":"job-2","state":"Finished"}]}`
+	canceled := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/jobs":
+			_, _ = w.Write([]byte(jobs))
+		case "/api/v1/jobs/job-1/cancel":
+			canceled = append(canceled, "job-1")
+			jobs = `{"jobs":[{"id":"job-1","state":"Canceling"},{"id":"job-2","state":"Finished"}]}`
+		}
+	}))
+	defer ts.Close()
+
+	teardown := &fakeTeardown{}
+	c := &defaultTiflowClusterControl{
+		cli:                   fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(sts).Build(),
+		recorder:              record.NewFakeRecorder(10),
+		masterMemberManager:   &fakeMemberManager{name: "master", teardown: teardown},
+		executorMemberManager: &fakeMemberManager{name: "executor", teardown: teardown},
+		pvcPruner:             &fakePVCPruner{teardown: teardown},
+		getMasterClient: func(client.Client, string, string, string, bool) tiflowapi.MasterClient {
+			return tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil)
+		},
+	}
+
+	// running jobs block tearing down without force delete
+	err := c.DeleteTiflowCluster(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Empty(t, teardown.steps)
+	require.Empty(t, canceled)
+
+	// running jobs are canceled by force delete, and waited for
+	tc.Annotations = map[string]string{label.AnnForceDeleteKey: label.AnnForceDeleteVal}
+	err = c.DeleteTiflowCluster(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, []string{"job-1"}, canceled)
+	err = c.DeleteTiflowCluster(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, []string{"job-1"}, canceled)
+	require.Empty(t, teardown.steps)
+
+	// executors are torn down before masters, PVCs are reclaimed at last
+	jobs = `{"jobs":[{"id":"job-1","state":"Canceled"},{"id":"job-2","state":"Finished"}]}`
+	require.NoError(t, c.DeleteTiflowCluster(ctx, tc))
+	require.Equal(t, []string{"executor", "master", "reclaim"}, teardown.steps)
+}
@@ -12,6 +12,12 @@ import (
 var (
 	// ControllerKind contains the group version for tiflowcluster controller type.
 	ControllerKind = pingcapcomv1alpha1.GroupVersion.WithKind("TiflowCluster")
+	// MonitorControllerKind contains the group version for tiflowmonitor controller type.
+	MonitorControllerKind = pingcapcomv1alpha1.GroupVersion.WithKind("TiflowMonitor")
+
+	// DiscoveryImage is the image of discovery service and the preStop hooks of tiflow components,
+	// it's the image of tiflow-operator itself
+	DiscoveryImage = "gcr.io/pingcap-public/tidbcloud/tiflow-operator:latest"
 )
 
 // RequeueError is used to requeue the item, this error type should't be considered as a real error
@@ -69,6 +75,19 @@ func TiflowExecutorPeerMemberName(clusterName string) string {
 	return fmt.Sprintf("%s-tiflow-executor-peer", clusterName)
 }
 
+// DiscoveryMemberName returns the name of discovery deployment and service
+func DiscoveryMemberName(clusterName string) string {
+	return fmt.Sprintf("%s-discovery", clusterName)
+}
+
+// DiscoveryFullHost returns discovery full host
+func DiscoveryFullHost(clusterName, namespace, clusterDomain string) string {
+	if clusterDomain != "" {
+		clusterDomain = "." + clusterDomain
+	}
+	return fmt.Sprintf("%s-discovery.%s.svc%s", clusterName, namespace, clusterDomain)
+}
+
 // GetOwnerRef returns TiflowCluster's OwnerReference
 func GetOwnerRef(tc *pingcapcomv1alpha1.TiflowCluster) metav1.OwnerReference {
 	controller := true
@@ -83,6 +102,35 @@ func GetOwnerRef(tc *pingcapcomv1alpha1.TiflowCluster) metav1.OwnerReference {
 	}
 }
 
+// TiflowMonitorMemberName returns the name of the Deployment, ConfigMap and PVC of tiflow monitor
+func TiflowMonitorMemberName(monitorName string) string {
+	return fmt.Sprintf("%s-monitor", monitorName)
+}
+
+// TiflowMonitorPrometheusName returns the Prometheus service name of tiflow monitor
+func TiflowMonitorPrometheusName(monitorName string) string {
+	return fmt.Sprintf("%s-prometheus", monitorName)
+}
+
+// TiflowMonitorGrafanaName returns the Grafana service name of tiflow monitor
+func TiflowMonitorGrafanaName(monitorName string) string {
+	return fmt.Sprintf("%s-grafana", monitorName)
+}
+
+// GetMonitorOwnerRef returns TiflowMonitor's OwnerReference
+func GetMonitorOwnerRef(tm *pingcapcomv1alpha1.TiflowMonitor) metav1.OwnerReference {
+	controller := true
+	blockOwnerDeletion := true
+	return metav1.OwnerReference{
+		APIVersion:         MonitorControllerKind.GroupVersion().String(),
+		Kind:               MonitorControllerKind.Kind,
+		Name:               tm.GetName(),
+		UID:                tm.GetUID(),
+		Controller:         &controller,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+}
+
 // AnnProm adds annotations for prometheus scraping metrics
 func AnnProm(port int32) map[string]string {
 	return map[string]string{
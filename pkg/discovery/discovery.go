@@ -0,0 +1,80 @@
+package discovery
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+const (
+	// DefaultPort is the port discovery service listens on
+	DefaultPort = 10261
+
+	masterPort = 10240
+)
+
+// Discovery tells the alive tiflow-master members of a tiflow cluster
+type Discovery interface {
+	// Masters returns the comma separated addresses of tiflow-master members
+	Masters() string
+}
+
+type masterDiscovery struct {
+	masterClient tiflowapi.MasterClient
+	// fallback is returned if tiflow-master has never been reachable
+	fallback string
+
+	lock sync.Mutex
+	// lastAddresses is the last addresses returned by tiflow-master
+	lastAddresses string
+}
+
+// NewDiscovery returns a Discovery which asks tiflow-master for its members
+func NewDiscovery(masterClient tiflowapi.MasterClient, fallback string) Discovery {
+	return &masterDiscovery{
+		masterClient: masterClient,
+		fallback:     fallback,
+	}
+}
+
+// Masters returns the addresses of masters reported by tiflow-master,
+// the last known addresses are returned if tiflow-master is unreachable.
+func (d *masterDiscovery) Masters() string {
+	// the lock isn't held while asking tiflow-master, so a slow tiflow-master doesn't block other requests
+	mastersInfo, err := d.masterClient.GetMasters()
+	if err == nil {
+		addresses := make([]string, 0, len(mastersInfo.Masters))
+		for _, master := range mastersInfo.Masters {
+			if master.Address != "" {
+				addresses = append(addresses, master.Address)
+			}
+		}
+		if len(addresses) > 0 {
+			sort.Strings(addresses)
+			d.lock.Lock()
+			defer d.lock.Unlock()
+			d.lastAddresses = strings.Join(addresses, ",")
+			return d.lastAddresses
+		}
+		err = fmt.Errorf("no master is returned")
+	}
+
+	klog.Errorf("failed to get masters from %s, error: %v", d.masterClient.GetURL(), err)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.lastAddresses != "" {
+		return d.lastAddresses
+	}
+	return d.fallback
+}
+
+// masterServiceAddress returns the address of tiflow-master service, it's used if no master is known
+func masterServiceAddress(namespace, tcName, clusterDomain string) string {
+	return fmt.Sprintf("%s:%d", controller.TiflowMasterFullHost(tcName, namespace, clusterDomain), masterPort)
+}
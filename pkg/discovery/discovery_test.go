@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+func TestDiscoveryMasters(t *testing.T) {
+	alive := true
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !alive {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"masters":[{"name":"m1","address":"demo-tiflow-master-1.demo-tiflow-master-peer.ns.svc:10240"},` +
+			`{"name":"m0","address":"demo-tiflow-master-0.demo-tiflow-master-peer.ns.svc:10240"}]}`))
+	}))
+	defer ts.Close()
+
+	require.Equal(t, "demo-tiflow-master.ns.svc.cluster.local:10240", masterServiceAddress("ns", "demo", "cluster.local"))
+	fallback := masterServiceAddress("ns", "demo", "")
+	require.Equal(t, "demo-tiflow-master.ns.svc:10240", fallback)
+
+	alive = false
+	d := NewDiscovery(tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil), fallback)
+	require.Equal(t, fallback, d.Masters())
+
+	alive = true
+	expected := "demo-tiflow-master-0.demo-tiflow-master-peer.ns.svc:10240,demo-tiflow-master-1.demo-tiflow-master-peer.ns.svc:10240"
+	require.Equal(t, expected, d.Masters())
+
+	// the last known masters are kept while tiflow-master is unreachable
+	alive = false
+	require.Equal(t, expected, d.Masters())
+}
+
+func TestDiscoveryMastersNotBlocked(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-release
+		_, _ = w.Write([]byte(`{"masters":[{"name":"m0","address":"demo-tiflow-master-0.demo-tiflow-master-peer.ns.svc:10240"}]}`))
+	}))
+	defer ts.Close()
+
+	d := NewDiscovery(tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil), "").(*masterDiscovery)
+	done := make(chan string)
+	go func() { done <- d.Masters() }()
+
+	// the lock is free while tiflow-master is being asked
+	<-entered
+	require.True(t, d.lock.TryLock())
+	d.lock.Unlock()
+	close(release)
+	require.Equal(t, "demo-tiflow-master-0.demo-tiflow-master-peer.ns.svc:10240", <-done)
+}
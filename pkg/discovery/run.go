@@ -0,0 +1,43 @@
+package discovery
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+// Run starts the discovery service of a tiflow cluster, args are the command line arguments after `discovery`
+func Run(args []string) error {
+	var tcName, namespace, clusterDomain, certDir string
+	var port int
+	fs := flag.NewFlagSet("discovery", flag.ContinueOnError)
+	fs.StringVar(&tcName, "tc-name", "", "The name of tiflow cluster whose masters are discovered.")
+	fs.StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of tiflow cluster.")
+	fs.StringVar(&clusterDomain, "cluster-domain", "", "The cluster domain of tiflow cluster.")
+	fs.IntVar(&port, "port", DefaultPort, "The port discovery service listens on.")
+	fs.StringVar(&certDir, "tls-cert-dir", "", "The directory of client certificates of tiflow-master, TLS is disabled if it's empty.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if tcName == "" || namespace == "" {
+		return fmt.Errorf("both --tc-name and --namespace should be set")
+	}
+
+	scheme := "http"
+	var tlsConfig *tls.Config
+	if certDir != "" {
+		scheme = "https"
+		var err error
+		tlsConfig, err = tiflowapi.LoadTLSConfigFromDir(certDir)
+		if err != nil {
+			return err
+		}
+	}
+	masterClient := tiflowapi.NewMasterClient(tiflowapi.MasterClientURL(namespace, tcName, "", scheme), tiflowapi.DefaultTimeout, tlsConfig)
+
+	d := NewDiscovery(masterClient, masterServiceAddress(namespace, tcName, clusterDomain))
+	return NewServer(d).ListenAndServe(fmt.Sprintf(":%d", port))
+}
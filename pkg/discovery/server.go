@@ -0,0 +1,50 @@
+package discovery
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// Server is the http server of discovery service
+type Server interface {
+	ListenAndServe(addr string) error
+}
+
+type server struct {
+	discovery Discovery
+}
+
+// NewServer returns a discovery Server
+func NewServer(discovery Discovery) Server {
+	return &server{discovery: discovery}
+}
+
+func (s *server) ListenAndServe(addr string) error {
+	klog.Infof("discovery service is listening on %s", addr)
+	return http.ListenAndServe(addr, s.handler())
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/masters", s.masters)
+	mux.HandleFunc("/healthz", s.healthz)
+	return mux
+}
+
+// masters writes the comma separated addresses of alive tiflow-master members,
+// it's used as the value of `--join` of tiflow-executor.
+func (s *server) masters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, fmt.Sprintf("method %s is not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := w.Write([]byte(s.discovery.Masters())); err != nil {
+		klog.Errorf("failed to write response, error: %v", err)
+	}
+}
+
+func (s *server) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,82 @@
+package event
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+// Reasons of the events recorded on TiflowCluster, they are shared by all the components of tiflow cluster.
+const (
+	// SuccessfulCreate is recorded when a statefulSet of tiflow cluster is created
+	SuccessfulCreate = "SuccessfulCreate"
+	// ScalingOut is recorded when a member is added to tiflow cluster
+	ScalingOut = "ScalingOut"
+	// ScalingIn is recorded when a member is removed from tiflow cluster
+	ScalingIn = "ScalingIn"
+	// UpgradePartition is recorded when the upgrade partition of a statefulSet is moved
+	UpgradePartition = "UpgradePartition"
+	// UpgradePod is recorded when a pod behind the upgrade partition is deleted to be recreated from the update revision
+	UpgradePod = "UpgradePod"
+	// RollbackUpgrade is recorded when a failed upgrade of a statefulSet is rolled back
+	RollbackUpgrade = "RollbackUpgrade"
+	// EvictLeader is recorded when the leader of tiflow-master is evicted
+	EvictLeader = "EvictLeader"
+	// FailedEvictLeader is recorded when the leader of tiflow-master can't be evicted
+	FailedEvictLeader = "FailedEvictLeader"
+	// DebugMode is recorded when a pod enters or leaves debug mode by its annotation
+	DebugMode = "DebugMode"
+	// DeletePod is recorded when a pod is deleted by its annotation
+	DeletePod = "DeletePod"
+	// PVCPruned is recorded when an unused PVC of tiflow-executor is deleted
+	PVCPruned = "PVCPruned"
+	// PVCReclaimed is recorded when a PVC of tiflow-executor is reclaimed during deleting
+	PVCReclaimed = "PVCReclaimed"
+	// Unhealthy is recorded when a pod of tiflow cluster is unhealthy
+	Unhealthy = "Unhealthy"
+	// FailedSetStoreLabels is recorded when labels of a store can't be set
+	FailedSetStoreLabels = "FailedSetStoreLabels"
+
+	unHealthEventMsgPattern = "%s pod[%s] is unhealthy, msg:%s"
+)
+
+// UnhealthyMessage returns the message of Unhealthy event
+func UnhealthyMessage(memberType v1alpha1.MemberType, podName, msg string) string {
+	return fmt.Sprintf(unHealthEventMsgPattern, memberType, podName, msg)
+}
+
+// FailedReason returns the reason of event recorded when a sync type of member is failed, such as FailedUpgrading
+func FailedReason(syncName v1alpha1.SyncTypeName, memberType v1alpha1.MemberType) string {
+	if memberType == v1alpha1.TiFlowMasterMemberType {
+		return fmt.Sprintf("Failed%s", syncName.GetMasterClusterPhase())
+	}
+	return fmt.Sprintf("Failed%s", syncName.GetExecutorClusterPhase())
+}
+
+// RecordFailedSyncTypes records a warning event for every sync type which turns to Failed or fails with a new message
+func RecordFailedSyncTypes(recorder record.EventRecorder, tc *v1alpha1.TiflowCluster, oldStatus *v1alpha1.TiflowClusterStatus) {
+	recordFailed(recorder, tc, v1alpha1.TiFlowMasterMemberType, oldStatus.Master.SyncTypes, tc.Status.Master.SyncTypes)
+	recordFailed(recorder, tc, v1alpha1.TiFlowExecutorMemberType, oldStatus.Executor.SyncTypes, tc.Status.Executor.SyncTypes)
+}
+
+func recordFailed(recorder record.EventRecorder, tc *v1alpha1.TiflowCluster, memberType v1alpha1.MemberType,
+	oldSyncTypes, newSyncTypes []v1alpha1.ClusterSyncType) {
+	for _, sync := range newSyncTypes {
+		if sync.Status != v1alpha1.Failed {
+			continue
+		}
+		failedBefore := false
+		for _, old := range oldSyncTypes {
+			if old.Name == sync.Name && old.Status == v1alpha1.Failed && old.Message == sync.Message {
+				failedBefore = true
+				break
+			}
+		}
+		if !failedBefore {
+			recorder.Event(tc, corev1.EventTypeWarning, FailedReason(sync.Name, memberType), sync.Message)
+		}
+	}
+}
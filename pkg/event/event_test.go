@@ -0,0 +1,36 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestRecordFailedSyncTypes(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{}
+	tc.Status.Master.SyncTypes = []v1alpha1.ClusterSyncType{
+		{Name: v1alpha1.UpgradeType, Status: v1alpha1.Failed, Message: "upgrading failed"},
+	}
+	tc.Status.Executor.SyncTypes = []v1alpha1.ClusterSyncType{
+		{Name: v1alpha1.ScaleOutType, Status: v1alpha1.Ongoing, Message: "scaling out..."},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	RecordFailedSyncTypes(recorder, tc, &v1alpha1.TiflowClusterStatus{})
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, "Warning FailedUpgrading upgrading failed", <-recorder.Events)
+
+	// the same failure is not recorded twice
+	RecordFailedSyncTypes(recorder, tc, tc.Status.DeepCopy())
+	require.Len(t, recorder.Events, 0)
+
+	old := tc.Status.DeepCopy()
+	tc.Status.Executor.SyncTypes[0].Status = v1alpha1.Failed
+	tc.Status.Executor.SyncTypes[0].Message = "scaling out failed"
+	RecordFailedSyncTypes(recorder, tc, old)
+	require.Len(t, recorder.Events, 1)
+	require.Equal(t, "Warning FailedScalingOut scaling out failed", <-recorder.Events)
+}
@@ -9,4 +9,6 @@ import (
 type TiflowManager interface {
 	// Sync implements the logic for syncing tiflowCluster.
 	Sync(context.Context, *pingcapcomv1alpha1.TiflowCluster) error
+	// Delete implements the logic for tearing down tiflowCluster's members before it's removed.
+	Delete(context.Context, *pingcapcomv1alpha1.TiflowCluster) error
 }
@@ -0,0 +1,244 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/component"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/discovery"
+	"github.com/pingcap/tiflow-operator/pkg/manager"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
+const (
+	discoveryPort = discovery.DefaultPort
+)
+
+type discoveryManager struct {
+	cli client.Client
+}
+
+// NewDiscoveryManager returns a manager of the discovery service,
+// which tells tiflow-executors the alive tiflow-master members.
+func NewDiscoveryManager(cli client.Client) manager.TiflowManager {
+	return &discoveryManager{
+		cli: cli,
+	}
+}
+
+func (m *discoveryManager) Sync(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	// only the cluster which has its own tiflow-master needs discovery service
+	if tc.Spec.Master == nil {
+		return nil
+	}
+
+	if err := m.syncDiscoveryService(ctx, tc); err != nil {
+		return err
+	}
+
+	return m.syncDiscoveryDeployment(ctx, tc)
+}
+
+// Delete leaves the discovery deployment and service to the garbage collector.
+func (m *discoveryManager) Delete(_ context.Context, _ *pingcapcomv1alpha1.TiflowCluster) error {
+	return nil
+}
+
+func (m *discoveryManager) syncDiscoveryService(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	svcName := controller.DiscoveryMemberName(tc.GetName())
+
+	newSvc := getNewDiscoveryService(tc)
+	oldSvc := &corev1.Service{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: svcName}, oldSvc)
+	if errors.IsNotFound(err) {
+		if err = controller.SetServiceLastAppliedConfigAnnotation(newSvc); err != nil {
+			return err
+		}
+		return m.cli.Create(ctx, newSvc)
+	}
+	if err != nil {
+		return fmt.Errorf("syncDiscoveryService: failed to get svc %s/%s, error: %v", ns, svcName, err)
+	}
+
+	equal, err := controller.ServiceEqual(newSvc, oldSvc)
+	if err != nil {
+		return err
+	}
+	if equal {
+		return nil
+	}
+
+	svc := oldSvc.DeepCopy()
+	svc.Spec = newSvc.Spec
+	svc.Labels = newSvc.Labels
+	if err = controller.SetServiceLastAppliedConfigAnnotation(svc); err != nil {
+		return err
+	}
+	// keep the cluster ip allocated by kubernetes
+	svc.Spec.ClusterIP = oldSvc.Spec.ClusterIP
+	svc.Spec.ClusterIPs = oldSvc.Spec.ClusterIPs
+	return m.cli.Update(ctx, svc)
+}
+
+func (m *discoveryManager) syncDiscoveryDeployment(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	deployName := controller.DiscoveryMemberName(tc.GetName())
+
+	newDeploy := getNewDiscoveryDeployment(tc)
+	oldDeploy := &apps.Deployment{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: deployName}, oldDeploy)
+	if errors.IsNotFound(err) {
+		if err = mngerutils.SetDeploymentLastAppliedConfigAnnotation(newDeploy); err != nil {
+			return err
+		}
+		klog.Infof("tiflow cluster: [%s/%s] creating discovery deployment", ns, tc.GetName())
+		return m.cli.Create(ctx, newDeploy)
+	}
+	if err != nil {
+		return fmt.Errorf("syncDiscoveryDeployment: failed to get deployment %s/%s, error: %v", ns, deployName, err)
+	}
+
+	if mngerutils.DeploymentEqual(newDeploy, oldDeploy) {
+		return nil
+	}
+
+	if err = mngerutils.SetDeploymentLastAppliedConfigAnnotation(newDeploy); err != nil {
+		return err
+	}
+	deploy := oldDeploy.DeepCopy()
+	deploy.Labels = newDeploy.Labels
+	if deploy.Annotations == nil {
+		deploy.Annotations = map[string]string{}
+	}
+	deploy.Annotations[mngerutils.LastAppliedConfigAnnotation] = newDeploy.Annotations[mngerutils.LastAppliedConfigAnnotation]
+	deploy.Spec.Replicas = newDeploy.Spec.Replicas
+	deploy.Spec.Template = newDeploy.Spec.Template
+	return m.cli.Update(ctx, deploy)
+}
+
+func getNewDiscoveryService(tc *pingcapcomv1alpha1.TiflowCluster) *corev1.Service {
+	discoverySelector := label.New().Instance(tc.GetInstanceName()).Discovery()
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.DiscoveryMemberName(tc.Name),
+			Namespace:       tc.Namespace,
+			Labels:          discoverySelector.Copy().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "discovery",
+					Port:       discoveryPort,
+					TargetPort: intstr.FromInt(discoveryPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector: discoverySelector.Labels(),
+		},
+	}
+}
+
+func getNewDiscoveryDeployment(tc *pingcapcomv1alpha1.TiflowCluster) *apps.Deployment {
+	baseMasterSpec := component.BuildMasterSpec(tc)
+	discoveryLabels := label.New().Instance(tc.GetInstanceName()).Discovery()
+
+	args := []string{
+		"discovery",
+		"--tc-name", tc.Name,
+		"--port", strconv.Itoa(discoveryPort),
+	}
+	if tc.Spec.ClusterDomain != "" {
+		args = append(args, "--cluster-domain", tc.Spec.ClusterDomain)
+	}
+	var volMounts []corev1.VolumeMount
+	var vols []corev1.Volume
+	if tc.IsClusterTLSEnabled() {
+		args = append(args, "--tls-cert-dir", clientCertPath)
+		volMounts = append(volMounts, corev1.VolumeMount{
+			Name: "tiflow-client-tls", ReadOnly: true, MountPath: clientCertPath,
+		})
+		vols = append(vols, corev1.Volume{
+			Name: "tiflow-client-tls", VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: util.ClusterClientTLSSecretName(tc.Name),
+				},
+			},
+		})
+	}
+
+	discoveryContainer := corev1.Container{
+		Name:            label.DiscoveryLabelVal,
+		Image:           controller.DiscoveryImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/manager"},
+		Args:            args,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "discovery",
+				ContainerPort: discoveryPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name: "NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
+				},
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromInt(discoveryPort),
+				},
+			},
+		},
+		VolumeMounts: volMounts,
+	}
+
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.DiscoveryMemberName(tc.Name),
+			Namespace:       tc.Namespace,
+			Labels:          discoveryLabels.Copy().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: apps.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: discoveryLabels.LabelSelector(),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: discoveryLabels.Copy().Labels(),
+				},
+				Spec: corev1.PodSpec{
+					Containers:       []corev1.Container{discoveryContainer},
+					Volumes:          vols,
+					ImagePullSecrets: baseMasterSpec.ImagePullSecrets(),
+				},
+			},
+		},
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -22,9 +23,11 @@ import (
 	"github.com/pingcap/tiflow-operator/pkg/component"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	"github.com/pingcap/tiflow-operator/pkg/manager"
 	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
 	"github.com/pingcap/tiflow-operator/pkg/status"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 	"github.com/pingcap/tiflow-operator/pkg/util"
 )
 
@@ -46,18 +49,20 @@ const (
 type executorMemberManager struct {
 	cli       client.Client
 	clientSet kubernetes.Interface
+	recorder  record.EventRecorder
 	scaler    Scaler
 	upgrader  Upgrader
 }
 
-func NewExecutorMemberManager(cli client.Client, clientSet kubernetes.Interface) manager.TiflowManager {
+func NewExecutorMemberManager(cli client.Client, clientSet kubernetes.Interface, recorder record.EventRecorder) manager.TiflowManager {
 
 	// todo: need to implement the logic for Failover
 	return &executorMemberManager{
 		cli:       cli,
 		clientSet: clientSet,
-		scaler:    NewExecutorScaler(clientSet),
-		upgrader:  NewExecutorUpgrader(cli),
+		recorder:  recorder,
+		scaler:    NewExecutorScaler(clientSet, recorder),
+		upgrader:  NewExecutorUpgrader(cli, recorder),
 	}
 }
 
@@ -81,8 +86,216 @@ func (m *executorMemberManager) Sync(ctx context.Context, tc *v1alpha1.TiflowClu
 		return err
 	}
 
+	// Sync tiflow-executor Service and the Services of each Pod
+	if err := m.syncExecutorServiceForTiflowCluster(ctx, tc); err != nil {
+		return err
+	}
+	if err := m.syncExecutorPodServicesForTiflowCluster(ctx, tc); err != nil {
+		return err
+	}
+
+	// Sync tiflow-executor PodDisruptionBudget
+	if err := syncExecutorPodDisruptionBudget(ctx, m.cli, tc); err != nil {
+		return err
+	}
+
+	// Suspend tiflow-executor StatefulSet, services, configmap and PVCs are kept
+	if tc.IsPaused() {
+		return m.suspendExecutorStatefulSet(ctx, tc)
+	}
+
+	// Heterogeneous executors join the master of the referenced cluster, block them until it's running.
+	// The remote master in another Kubernetes cluster can't be watched, it's checked by status syncing.
+	if tc.Heterogeneous() && !tc.RemoteMaster() {
+		if err := m.checkReferencedMaster(ctx, tc); err != nil {
+			return err
+		}
+	}
+
 	// Sync tilfow-Executor StatefulSet
-	return m.syncExecutorStatefulSetForTiflowCluster(ctx, tc)
+	if err := m.syncExecutorStatefulSetForTiflowCluster(ctx, tc); err != nil {
+		return err
+	}
+
+	// Process the operations requested by the annotations of tiflow-executor pods
+	return syncPodOperations(ctx, m.cli, m.recorder, tc, v1alpha1.TiFlowExecutorMemberType)
+}
+
+// checkReferencedMaster makes sure the tiflow-master of the cluster referenced by spec.cluster is running,
+// and records the result in ReferencedMasterChecked condition.
+func (m *executorMemberManager) checkReferencedMaster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	refNs, refName := tc.ReferencedCluster()
+
+	refTc := &v1alpha1.TiflowCluster{}
+	err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: refNs,
+		Name:      refName,
+	}, refTc)
+	if errors.IsNotFound(err) {
+		condition.SetFalseWithReason(v1alpha1.ReferencedMasterChecked, tc.GetClusterStatus(), v1alpha1.ReferencedClusterNotFoundReason,
+			fmt.Sprintf("referenced cluster [%s/%s] is not found", refNs, refName), metav1.Now())
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is blocked, referenced cluster [%s/%s] is not found",
+			ns, tcName, refNs, refName)
+	}
+	if err != nil {
+		return fmt.Errorf("checkReferencedMaster: failed to get referenced cluster [%s/%s] for cluster [%s/%s], error: %s",
+			refNs, refName, ns, tcName, err)
+	}
+
+	if refTc.WithoutLocalMaster() || !refTc.GetDeletionTimestamp().IsZero() || refTc.GetMasterPhase() != v1alpha1.MasterRunning {
+		condition.SetFalseWithReason(v1alpha1.ReferencedMasterChecked, tc.GetClusterStatus(), v1alpha1.ReferencedMasterNotReadyReason,
+			fmt.Sprintf("tiflow-master of referenced cluster [%s/%s] is not running, phase: %s", refNs, refName, refTc.GetMasterPhase()), metav1.Now())
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is blocked, tiflow-master of referenced cluster [%s/%s] is not running, phase: %s",
+			ns, tcName, refNs, refName, refTc.GetMasterPhase())
+	}
+
+	condition.SetTrue(v1alpha1.ReferencedMasterChecked, tc.GetClusterStatus(), metav1.Now())
+	return nil
+}
+
+// suspendExecutorStatefulSet scales the tiflow-executor statefulSet to zero.
+func (m *executorMemberManager) suspendExecutorStatefulSet(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	sts := &appsv1.StatefulSet{}
+	err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowExecutorMemberName(tcName),
+	}, sts)
+	if errors.IsNotFound(err) {
+		// nothing to scale in, the statefulSet is never created or has been deleted
+		completeSuspend(tc, v1alpha1.TiFlowExecutorMemberType, "executor suspending completed")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("suspendExecutor: failed to get sts %s for cluster [%s/%s], error: %s",
+			controller.TiflowExecutorMemberName(tcName), ns, tcName, err)
+	}
+
+	tc.Status.Executor.StatefulSet = sts.Status.DeepCopy()
+	condition.SetFalse(v1alpha1.ExecutorSyncChecked, tc.GetClusterStatus(), metav1.Now())
+
+	removed, err := scaleStatefulSetToZero(ctx, m.clientSet, sts)
+	if err != nil {
+		status.Failed(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+			fmt.Sprintf("tiflow executor [%s/%s] suspending failed", ns, tcName))
+		return err
+	}
+	if !removed {
+		status.Ongoing(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+			fmt.Sprintf("tiflow executor [%s/%s] suspending...", ns, tcName))
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is suspending, %d pods left", ns, tcName, sts.Status.Replicas)
+	}
+
+	completeSuspend(tc, v1alpha1.TiFlowExecutorMemberType, "executor suspending completed")
+	return nil
+}
+
+// waitForMasterResumed makes sure the local tiflow-master is ready and has elected a leader before executors are resumed.
+func (m *executorMemberManager) waitForMasterResumed(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.WithoutLocalMaster() {
+		return nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	if err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowMasterMemberName(tcName),
+	}, sts); err != nil {
+		return fmt.Errorf("resumeExecutor: failed to get master sts %s for cluster [%s/%s], error: %s",
+			controller.TiflowMasterMemberName(tcName), ns, tcName, err)
+	}
+
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas == 0 || sts.Status.ReadyReplicas < *sts.Spec.Replicas {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is waiting for tiflow-master to be ready", ns, tcName)
+	}
+
+	if _, err := getMasterClient(m.cli, ns, tcName, "", tc.IsClusterTLSEnabled()).GetLeader(); err != nil {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is waiting for tiflow-master leader, error: %v", ns, tcName, err)
+	}
+
+	return nil
+}
+
+// Delete scales the tiflow-executor statefulSet to zero and waits for all executors to deregister from tiflow-master.
+// The statefulSet, services and configmap are left to the garbage collector.
+func (m *executorMemberManager) Delete(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.Spec.Executor == nil {
+		return nil
+	}
+
+	sts := &appsv1.StatefulSet{}
+	err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowExecutorMemberName(tcName),
+	}, sts)
+	if errors.IsNotFound(err) {
+		status.Completed(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+			"executor deleting completed")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleteExecutor: failed to get sts %s for cluster [%s/%s], error: %s",
+			controller.TiflowExecutorMemberName(tcName), ns, tcName, err)
+	}
+
+	klog.Infof("start to delete tiflow executor [%s/%s]", ns, tcName)
+	condition.SetFalse(v1alpha1.ExecutorSyncChecked, tc.GetClusterStatus(), metav1.Now())
+	status.Ongoing(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+		fmt.Sprintf("tiflow executor [%s/%s] deleting...", ns, tcName))
+
+	removed, err := scaleStatefulSetToZero(ctx, m.clientSet, sts)
+	if err != nil {
+		status.Failed(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+			fmt.Sprintf("tiflow executor [%s/%s] deleting failed", ns, tcName))
+		return err
+	}
+	if !removed {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is deleting, %d pods left", ns, tcName, sts.Status.Replicas)
+	}
+
+	registered, err := m.registeredExecutors(tc)
+	if err != nil {
+		// the master may have been broken already, it's no use to wait for it.
+		klog.Warningf("tiflow cluster: [%s/%s] can not get executors from tiflow-master, skip waiting for deregistering, error: %v",
+			ns, tcName, err)
+	} else if registered > 0 {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-executor is deleting, waiting for %d executors to deregister",
+			ns, tcName, registered)
+	}
+
+	status.Completed(v1alpha1.DeleteType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+		"executor deleting completed")
+	return nil
+}
+
+// registeredExecutors returns the number of this cluster's executors which are still registered in tiflow-master
+func (m *executorMemberManager) registeredExecutors(tc *v1alpha1.TiflowCluster) (int, error) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	executorsInfo, err := tiflowapi.GetJoinedMasterClient(m.cli, tc).GetExecutors()
+	if err != nil {
+		return 0, err
+	}
+
+	registered := 0
+	for _, e := range executorsInfo.Executors {
+		clusterName, _, namespace, err := getOrdinalFromName(e.Name, v1alpha1.TiFlowExecutorMemberType)
+		if err == nil && clusterName == tcName && namespace == ns {
+			registered++
+		}
+	}
+	return registered, nil
 }
 
 // syncExecutorConfigMap implements the logic for syncing configMap of executor.
@@ -155,6 +368,51 @@ func (m *executorMemberManager) syncExecutorHeadlessServiceForTiflowCluster(ctx
 	return nil
 }
 
+// syncExecutorServiceForTiflowCluster syncs the service load balancing all executors if spec.executor.service is set,
+// and removes the service once it's unset.
+func (m *executorMemberManager) syncExecutorServiceForTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	if tc.Spec.Executor.Service == nil {
+		return deleteOwnedService(ctx, m.cli, tc, controller.TiflowExecutorMemberName(tc.GetName()))
+	}
+	return syncService(ctx, m.cli, getNewExecutorService(tc))
+}
+
+// syncExecutorPodServicesForTiflowCluster syncs a service for each executor if spec.executor.podService is set,
+// the services of executors which are scaled in, or all of them if podService is unset, are removed.
+func (m *executorMemberManager) syncExecutorPodServicesForTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	desired := map[string]bool{}
+	if tc.Spec.Executor.PodService != nil {
+		for ordinal := int32(0); ordinal < tc.Spec.Executor.Replicas; ordinal++ {
+			svc := getNewExecutorPodService(tc, ordinal)
+			desired[svc.Name] = true
+			if err := syncService(ctx, m.cli, svc); err != nil {
+				return err
+			}
+		}
+	}
+
+	svcList := &corev1.ServiceList{}
+	selector := label.New().Instance(tc.GetInstanceName()).TiflowExecutor().Labels()
+	selector[label.PodServiceLabelKey] = label.PodServiceLabelVal
+	if err := m.cli.List(ctx, svcList, client.InNamespace(ns), client.MatchingLabels(selector)); err != nil {
+		return fmt.Errorf("syncExecutorPodServices: failed to list pod services of cluster [%s/%s], error: %v", ns, tcName, err)
+	}
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if desired[svc.Name] || !metav1.IsControlledBy(svc, tc) {
+			continue
+		}
+		klog.Infof("tiflow cluster: [%s/%s] removing service %s of scaled in tiflow-executor", ns, tcName, svc.Name)
+		if err := client.IgnoreNotFound(m.cli.Delete(ctx, svc)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // syncExecutorStatefulSetForTiflowCluster implements the logic for syncing statefulSet of executor.
 func (m *executorMemberManager) syncExecutorStatefulSetForTiflowCluster(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
 	ns := tc.GetNamespace()
@@ -209,11 +467,29 @@ func (m *executorMemberManager) syncExecutorStatefulSetForTiflowCluster(ctx cont
 				fmt.Sprintf("create executor cluster [%s/%s] failed", ns, tcName))
 			return err
 		}
+		m.recorder.Eventf(tc, corev1.EventTypeNormal, event.SuccessfulCreate, "create tiflow-executor statefulSet %s with %d replicas",
+			newSts.GetName(), *newSts.Spec.Replicas)
 
 		tc.Status.Executor.StatefulSet = &appsv1.StatefulSetStatus{}
 		return nil
 	}
 
+	// Resume the suspended cluster only after tiflow-master is back
+	if NeedResume(tc, v1alpha1.TiFlowExecutorMemberType) {
+		if err := m.waitForMasterResumed(ctx, tc); err != nil {
+			return err
+		}
+
+		condition.SetFalse(v1alpha1.ExecutorSyncChecked, tc.GetClusterStatus(), metav1.Now())
+		status.Remove(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType)
+		status.Ongoing(v1alpha1.ResumeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+			fmt.Sprintf("start to resume executor cluster [%s/%s]", ns, tcName))
+		return scaleStatefulSet(ctx, m.clientSet, oldSts, *newSts.Spec.Replicas)
+	}
+
+	keepBlockedVersion(tc, v1alpha1.TiFlowExecutorMemberType, newSts, oldSts)
+	keepPrestopImage(newSts, oldSts)
+
 	if condition.False(v1alpha1.ExecutorSyncChecked, tc.GetClusterConditions()) {
 		// Force Update takes precedence over Scaling
 		if NeedForceUpgrade(tc.Annotations) {
@@ -238,8 +514,11 @@ func (m *executorMemberManager) syncExecutorStatefulSetForTiflowCluster(ctx cont
 
 	if !templateEqual(newSts, oldSts) || tc.Status.Executor.Phase == v1alpha1.ExecutorUpgrading {
 		if err := m.upgrader.Upgrade(tc, oldSts, newSts); err != nil {
-			status.Failed(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
-				fmt.Sprintf("tiflow executor [%s/%s] upgrading failed", ns, tcName))
+			// a RequeueError means the upgrade is waiting, whose reason is reported by the upgrader
+			if !controller.IsRequeueError(err) {
+				status.Failed(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
+					fmt.Sprintf("tiflow executor [%s/%s] upgrading failed", ns, tcName))
+			}
 			return err
 		}
 	}
@@ -254,27 +533,56 @@ func (m *executorMemberManager) getExecutorConfigMap(tc *v1alpha1.TiflowCluster)
 	if tc.Spec.Executor.Config != nil {
 		config = tc.Spec.Executor.Config.DeepCopy()
 	}
+	// the certificates mounted at clusterCertPath are used to connect to tiflow-master
+	if tc.IsClusterTLSEnabled() || executorUseRemoteTLS(tc) {
+		setSecurityConfig(config, clusterCertPath)
+	}
 
 	configText, err := config.MarshalTOML()
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: add discovery or full name to make sure executor can connect to alive master
+	// the addresses given by users are rendered into the start script
+	if err := validateHostPorts("spec.executor.advertiseAddresses", tc.Spec.Executor.AdvertiseAddresses); err != nil {
+		return nil, err
+	}
+	if tc.Spec.Cluster != nil {
+		if err := validateHostPorts("spec.cluster.masterAddresses", tc.Spec.Cluster.MasterAddresses); err != nil {
+			return nil, err
+		}
+	}
+
+	// the static addresses are used only if the discovery service is unavailable
 	masterAddresses := make([]string, 0)
+	discoveryAddress := ""
 	if !tc.WithoutLocalMaster() {
 		masterAddresses = append(masterAddresses, controller.TiflowMasterMemberName(tc.Name)+":10240")
-	}
-	if tc.Heterogeneous() {
-		masterAddresses = append(masterAddresses, controller.TiflowMasterFullHost(tc.Spec.Cluster.Name, tc.Spec.Cluster.Namespace, tc.Spec.ClusterDomain)+":10240") // use dm-master of reference cluster
+		discoveryAddress = fmt.Sprintf("%s:%d", controller.DiscoveryMemberName(tc.Name), discoveryPort)
+	}
+	if tc.RemoteMaster() {
+		// use the externally reachable tiflow-master in another Kubernetes cluster
+		masterAddresses = append(masterAddresses, tc.Spec.Cluster.MasterAddresses...)
+	} else if tc.Heterogeneous() {
+		clusterDomain := tc.Spec.ClusterDomain
+		if tc.Spec.Cluster.ClusterDomain != "" {
+			clusterDomain = tc.Spec.Cluster.ClusterDomain
+		}
+		refNs, refName := tc.ReferencedCluster()
+		masterAddresses = append(masterAddresses, controller.TiflowMasterFullHost(refName, refNs, clusterDomain)+":10240") // use tiflow-master of reference cluster
+		if tc.WithoutLocalMaster() {
+			discoveryAddress = fmt.Sprintf("%s:%d", controller.DiscoveryFullHost(refName, refNs, clusterDomain), discoveryPort)
+		}
 	}
 
 	startScript, err := RenderExecutorStartScript(&TiflowExecutorStartScriptModel{
 		CommonModel: CommonModel{
 			ClusterDomain: tc.Spec.ClusterDomain,
 		},
-		DataDir:       tiflowExecutorDataVolumeMountPath,
-		MasterAddress: strings.Join(masterAddresses, ","),
+		DataDir:            tiflowExecutorDataVolumeMountPath,
+		MasterAddress:      strings.Join(masterAddresses, ","),
+		AdvertiseAddresses: strings.Join(tc.Spec.Executor.AdvertiseAddresses, " "),
+		DiscoveryAddress:   discoveryAddress,
 	})
 	if err != nil {
 		return nil, err
@@ -338,6 +646,52 @@ func (m *executorMemberManager) getNewExecutorHeadlessService(tc *v1alpha1.Tiflo
 	return &svc
 }
 
+// getNewExecutorService returns the service load balancing all executors by spec.executor.service.
+func getNewExecutorService(tc *v1alpha1.TiflowCluster) *corev1.Service {
+	executorSelector := label.New().Instance(tc.GetInstanceName()).TiflowExecutor()
+	svc := newExecutorClientService(tc, controller.TiflowExecutorMemberName(tc.Name), executorSelector.Copy().Labels(), executorSelector.Labels())
+	applyServiceSpec(svc, tc.Spec.Executor.Service, true)
+	return svc
+}
+
+// getNewExecutorPodService returns the service of the executor with the given ordinal by spec.executor.podService.
+func getNewExecutorPodService(tc *v1alpha1.TiflowCluster, ordinal int32) *corev1.Service {
+	podName := TiflowExecutorPodName(tc.Name, ordinal)
+	svcLabels := label.New().Instance(tc.GetInstanceName()).TiflowExecutor().Labels()
+	svcLabels[label.PodServiceLabelKey] = label.PodServiceLabelVal
+	selector := label.New().Instance(tc.GetInstanceName()).TiflowExecutor().Labels()
+	selector[appsv1.StatefulSetPodNameLabel] = podName
+
+	svc := newExecutorClientService(tc, podName, svcLabels, selector)
+	applyServiceSpec(svc, tc.Spec.Executor.PodService, false)
+	return svc
+}
+
+// newExecutorClientService returns a service of executors for clients, its port is named differently from
+// the headless service, so that executors are not scraped repeatedly by the ServiceMonitor.
+func newExecutorClientService(tc *v1alpha1.TiflowCluster, name string, svcLabels, selector map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tc.Namespace,
+			Labels:          svcLabels,
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "tiflow-executor-client",
+					Port:       executorPort,
+					TargetPort: intstr.FromInt(executorPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector: selector,
+		},
+	}
+}
+
 // getNewExecutorStatefulSet returns a new statefulSet of executor by tiflowCluster Spec.
 func (m *executorMemberManager) getNewExecutorStatefulSet(ctx context.Context, tc *v1alpha1.TiflowCluster, cfgMap *corev1.ConfigMap) (*appsv1.StatefulSet, error) {
 	ns := tc.GetNamespace()
@@ -400,7 +754,7 @@ func (m *executorMemberManager) getNewExecutorPodTemp(tc *v1alpha1.TiflowCluster
 	executorContainer := m.getNewExecutorContainers(tc)
 	podSpec.Containers = append(executorContainer, baseExecutorSpec.AdditionalContainers()...)
 
-	var initContainers []corev1.Container
+	initContainers := []corev1.Container{prestopInitContainer()}
 	podSpec.InitContainers = append(initContainers, baseExecutorSpec.InitContainers()...)
 
 	// todo: More information about PodSpec will be modified in the near future
@@ -476,6 +830,7 @@ func (m *executorMemberManager) getNewExecutorPodVols(tc *v1alpha1.TiflowCluster
 	// handle vols for Pod
 	vols := []corev1.Volume{
 		annoVolume,
+		prestopVolume(),
 		{
 			Name: "config",
 			VolumeSource: corev1.VolumeSource{
@@ -509,11 +864,11 @@ func (m *executorMemberManager) getNewExecutorPodVols(tc *v1alpha1.TiflowCluster
 			},
 		},
 	}
-	if tc.IsClusterTLSEnabled() {
+	if tc.IsClusterTLSEnabled() || executorUseRemoteTLS(tc) {
 		vols = append(vols, corev1.Volume{
 			Name: "tiflow-executor-tls", VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
-					SecretName: util.ClusterTLSSecretName(tc.Name, label.TiflowMasterLabelVal),
+					SecretName: executorClusterTLSSecretName(tc),
 				},
 			},
 		})
@@ -568,6 +923,9 @@ func (m *executorMemberManager) getNewExecutorContainers(tc *v1alpha1.TiflowClus
 
 	volMounts := m.getNewExecutorContainerVolsMount(tc)
 
+	// tiflow-executor serves with the certificates of the remote tiflow-master as well if they are used
+	readinessProbe, livenessProbe, startupProbe := getComponentProbes(baseExecutorSpec, executorPort,
+		tc.IsClusterTLSEnabled() || executorUseRemoteTLS(tc))
 	executorContainer := []corev1.Container{
 		{
 			Name:            label.TiflowExecutorLabelVal,
@@ -581,10 +939,14 @@ func (m *executorMemberManager) getNewExecutorContainers(tc *v1alpha1.TiflowClus
 					Protocol:      corev1.ProtocolTCP,
 				},
 			},
-			Env:          env,
-			EnvFrom:      envFrom,
-			VolumeMounts: volMounts,
-			Resources:    controller.ContainerResource(tc.Spec.Executor.ResourceRequirements),
+			Env:            env,
+			EnvFrom:        envFrom,
+			VolumeMounts:   volMounts,
+			Resources:      controller.ContainerResource(tc.Spec.Executor.ResourceRequirements),
+			ReadinessProbe: readinessProbe,
+			LivenessProbe:  livenessProbe,
+			StartupProbe:   startupProbe,
+			Lifecycle:      executorPrestop(tc, baseExecutorSpec),
 		},
 	}
 
@@ -597,9 +959,10 @@ func (m *executorMemberManager) getNewExecutorContainerVolsMount(tc *v1alpha1.Ti
 	volMounts := []corev1.VolumeMount{
 		{Name: "config", ReadOnly: true, MountPath: tiflowExecutorDataVolumeMountPath},
 		{Name: "startup-script", ReadOnly: true, MountPath: "/usr/local/bin"},
+		prestopVolumeMount(),
 	}
 
-	if tc.IsClusterTLSEnabled() {
+	if tc.IsClusterTLSEnabled() || executorUseRemoteTLS(tc) {
 		volMounts = append(volMounts, corev1.VolumeMount{
 			Name: "tiflow-executor-tls", ReadOnly: true, MountPath: clusterCertPath,
 		})
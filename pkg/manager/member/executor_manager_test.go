@@ -1 +1,270 @@
 package member
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/status"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+func TestExecutorServices(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Executor: &v1alpha1.ExecutorSpec{
+				Replicas: 3,
+				Service: &v1alpha1.ServiceSpec{
+					Type:           corev1.ServiceTypeLoadBalancer,
+					LoadBalancerIP: pointer.StringPtr("10.0.0.1"),
+				},
+				PodService: &v1alpha1.ServiceSpec{
+					Type:                     corev1.ServiceTypeLoadBalancer,
+					LoadBalancerIP:           pointer.StringPtr("10.0.0.2"),
+					LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+					NodePort:                 pointer.Int32Ptr(30000),
+					Labels:                   map[string]string{"foo": "bar"},
+				},
+			},
+		},
+	}
+
+	svc := getNewExecutorService(tc)
+	require.Equal(t, "demo-tiflow-executor", svc.Name)
+	require.Equal(t, corev1.ServiceTypeLoadBalancer, svc.Spec.Type)
+	require.Equal(t, "10.0.0.1", svc.Spec.LoadBalancerIP)
+	require.Equal(t, "tiflow-executor-client", svc.Spec.Ports[0].Name)
+	require.NotContains(t, svc.Spec.Selector, appsv1.StatefulSetPodNameLabel)
+
+	podSvc := getNewExecutorPodService(tc, 2)
+	require.Equal(t, "demo-tiflow-executor-2", podSvc.Name)
+	require.Equal(t, "demo-tiflow-executor-2", podSvc.Spec.Selector[appsv1.StatefulSetPodNameLabel])
+	require.Equal(t, label.PodServiceLabelVal, podSvc.Labels[label.PodServiceLabelKey])
+	require.Equal(t, "bar", podSvc.Labels["foo"])
+	require.Equal(t, []string{"10.0.0.0/8"}, podSvc.Spec.LoadBalancerSourceRanges)
+	// the fields which can't be shared by services are ignored
+	require.Empty(t, podSvc.Spec.LoadBalancerIP)
+	require.Zero(t, podSvc.Spec.Ports[0].NodePort)
+}
+
+func TestSuspendAndResume(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master:   &v1alpha1.MasterSpec{Replicas: 3},
+			Executor: &v1alpha1.ExecutorSpec{Replicas: 3},
+			Paused:   true,
+		},
+	}
+	newSts := func(name string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name},
+			Spec:       appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+			Status:     appsv1.StatefulSetStatus{Replicas: 3, ReadyReplicas: 3},
+		}
+	}
+	masterSts, executorSts := newSts("demo-tiflow-master"), newSts("demo-tiflow-executor")
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "data-demo-tiflow-executor-0"}}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(masterSts, executorSts, pvc).Build()
+	clientSet, scaled := newScaleRecorder()
+	m := NewExecutorMemberManager(cli, clientSet, record.NewFakeRecorder(10))
+
+	// executors are scaled to zero, and waited for
+	err := m.Sync(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, map[string]int32{"demo-tiflow-executor": 0}, scaled)
+	require.Equal(t, v1alpha1.Ongoing, status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType))
+
+	executorSts.Spec.Replicas = pointer.Int32Ptr(0)
+	executorSts.Status = appsv1.StatefulSetStatus{}
+	require.NoError(t, cli.Update(ctx, executorSts))
+	require.NoError(t, m.Sync(ctx, tc))
+	require.Equal(t, v1alpha1.Completed, status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType))
+	// services and PVCs are kept for resuming
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor-peer"}, &corev1.Service{}))
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: pvc.Name}, &corev1.PersistentVolumeClaim{}))
+
+	// the master statefulSet which is never created is suspended at once
+	master := NewMasterMemberManager(cli, clientSet, record.NewFakeRecorder(10)).(*masterMemberManager)
+	require.NoError(t, cli.Delete(ctx, masterSts))
+	require.NoError(t, master.suspendMasterStatefulSet(ctx, tc))
+	require.Equal(t, v1alpha1.Completed, status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType))
+
+	// executors are resumed only after tiflow-master is back
+	tc.Spec.Paused = false
+	masterSts = newSts("demo-tiflow-master")
+	masterSts.ResourceVersion = ""
+	masterSts.Status.ReadyReplicas = 1
+	require.NoError(t, cli.Create(ctx, masterSts))
+	err = m.Sync(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, int32(0), scaled["demo-tiflow-executor"])
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"advertise_addr":"demo-tiflow-master-0.demo-tiflow-master-peer.ns-1.svc:10240"}`))
+	}))
+	defer ts.Close()
+	defer func(f func(client.Client, string, string, string, bool) tiflowapi.MasterClient) { getMasterClient = f }(getMasterClient)
+	getMasterClient = func(client.Client, string, string, string, bool) tiflowapi.MasterClient {
+		return tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil)
+	}
+	masterSts.Status.ReadyReplicas = 3
+	require.NoError(t, cli.Update(ctx, masterSts))
+	require.NoError(t, m.Sync(ctx, tc))
+	require.Equal(t, int32(3), scaled["demo-tiflow-executor"])
+	require.Equal(t, v1alpha1.Ongoing, status.GetSyncStatus(v1alpha1.ResumeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType))
+	require.Equal(t, v1alpha1.Unknown, status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType))
+}
+
+func TestCheckReferencedMaster(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Cluster:  &v1alpha1.ClusterRef{Name: "ref"},
+			Executor: &v1alpha1.ExecutorSpec{Replicas: 3},
+		},
+	}
+	cli := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	m := NewExecutorMemberManager(cli, nil, record.NewFakeRecorder(10)).(*executorMemberManager)
+	reason := func() string {
+		return meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.ReferencedMasterChecked)).Reason
+	}
+
+	err := m.checkReferencedMaster(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, v1alpha1.ReferencedClusterNotFoundReason, reason())
+
+	ref := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "ref"},
+		Spec:       v1alpha1.TiflowClusterSpec{Master: &v1alpha1.MasterSpec{Replicas: 3}},
+	}
+	ref.Status.Master.Phase = v1alpha1.MasterUpgrading
+	require.NoError(t, cli.Create(ctx, ref))
+	err = m.checkReferencedMaster(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, v1alpha1.ReferencedMasterNotReadyReason, reason())
+	require.Contains(t, meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.ReferencedMasterChecked)).Message, "ns-1/ref")
+
+	ref.Status.Master.Phase = v1alpha1.MasterRunning
+	require.NoError(t, cli.Update(ctx, ref))
+	require.NoError(t, m.checkReferencedMaster(ctx, tc))
+	require.True(t, meta.IsStatusConditionTrue(tc.Status.ClusterConditions, string(v1alpha1.ReferencedMasterChecked)))
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	require.NoError(t, v1alpha1.AddToScheme(s))
+	return s
+}
+
+func TestHeterogeneousExecutorConfigMap(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			// the namespace of the referenced cluster defaults to the one of tc
+			Cluster:  &v1alpha1.ClusterRef{Name: "ref"},
+			Executor: &v1alpha1.ExecutorSpec{Replicas: 3},
+		},
+	}
+	m := &executorMemberManager{}
+	cm, err := m.getExecutorConfigMap(tc)
+	require.NoError(t, err)
+	require.Contains(t, cm.Data["startup-script"], "ref-tiflow-master.ns-1.svc:10240")
+	require.NotContains(t, cm.Data["config-file"], "security")
+
+	// the referenced master is joined together with the local masters found by the discovery service
+	tc.Spec.Master = &v1alpha1.MasterSpec{Replicas: 3}
+	cm, err = m.getExecutorConfigMap(tc)
+	require.NoError(t, err)
+	require.Contains(t, cm.Data["startup-script"], "join=${masters},demo-tiflow-master:10240,ref-tiflow-master.ns-1.svc:10240")
+	tc.Spec.Master = nil
+
+	// the remote master is accessed by the client certificates of the referenced cluster
+	tc.Spec.Cluster.MasterAddresses = []string{"tiflow-master.example.com:10240"}
+	tc.Spec.TLSCluster = pointer.BoolPtr(true)
+	cm, err = m.getExecutorConfigMap(tc)
+	require.NoError(t, err)
+	require.Contains(t, cm.Data["startup-script"], "tiflow-master.example.com:10240")
+	require.Contains(t, cm.Data["config-file"], `ca-path = "/var/lib/tiflow-cluster-certs/ca.crt"`)
+	require.Contains(t, cm.Data["config-file"], `cert-path = "/var/lib/tiflow-cluster-certs/tls.crt"`)
+	require.Contains(t, cm.Data["config-file"], `key-path = "/var/lib/tiflow-cluster-certs/tls.key"`)
+	require.Equal(t, "ref-cluster-client-secret", executorClusterTLSSecretName(tc))
+	require.Equal(t, "https://tiflow-master.example.com:10240", tiflowapi.JoinedMasterURL(tc))
+
+	tc.Spec.Cluster.TLSClientSecretName = "remote-client-secret"
+	require.Equal(t, "remote-client-secret", executorClusterTLSSecretName(tc))
+
+	// TLS is off without any certificates of the remote master
+	tc.Spec.Cluster.TLSClientSecretName = ""
+	tc.Spec.TLSCluster = nil
+	require.False(t, executorUseRemoteTLS(tc))
+	require.Equal(t, "http://tiflow-master.example.com:10240", tiflowapi.JoinedMasterURL(tc))
+
+	// the addresses are rendered into the start script only if they are host:port
+	tc.Spec.Executor.AdvertiseAddresses = []string{"10.0.0.1:10241", "executor-1.example.com:10241"}
+	_, err = m.getExecutorConfigMap(tc)
+	require.NoError(t, err)
+	for _, addr := range []string{"a.com:10241;reboot", "$(reboot):10241", "a.com :10241", "a.com", "a.com:0"} {
+		tc.Spec.Executor.AdvertiseAddresses = []string{addr}
+		_, err = m.getExecutorConfigMap(tc)
+		require.Error(t, err, addr)
+	}
+	tc.Spec.Executor.AdvertiseAddresses = nil
+	tc.Spec.Cluster.MasterAddresses = []string{"tiflow-master.example.com:10240 `reboot`"}
+	_, err = m.getExecutorConfigMap(tc)
+	require.Error(t, err)
+}
+
+func TestExecutorProbes(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master:   &v1alpha1.MasterSpec{Replicas: 3},
+			Executor: &v1alpha1.ExecutorSpec{Replicas: 3},
+		},
+	}
+	m := &executorMemberManager{}
+	container := m.getNewExecutorContainers(tc)[0]
+	for _, probe := range []*corev1.Probe{container.ReadinessProbe, container.LivenessProbe, container.StartupProbe} {
+		require.Nil(t, probe.Exec)
+		require.Equal(t, "/metrics", probe.HTTPGet.Path)
+		require.Equal(t, executorPort, probe.HTTPGet.Port.IntValue())
+	}
+	require.Equal(t, int32(60), container.StartupProbe.FailureThreshold)
+
+	// executors serve with the client certificates of the remote tiflow-master, which are presented by the probes,
+	// the container isn't restarted while the pod is in debug mode
+	tc.Spec.Master = nil
+	tc.Spec.Cluster = &v1alpha1.ClusterRef{
+		Name:                "ref",
+		MasterAddresses:     []string{"tiflow-master.example.com:10240"},
+		TLSClientSecretName: "remote-client-secret",
+	}
+	container = m.getNewExecutorContainers(tc)[0]
+	for _, probe := range []*corev1.Probe{container.ReadinessProbe, container.LivenessProbe, container.StartupProbe} {
+		require.Nil(t, probe.HTTPGet)
+		require.Equal(t, []string{"/var/lib/tiflow-operator/manager", "probe",
+			"--port=10241", "--path=/metrics", "--tls-cert-dir=/var/lib/tiflow-cluster-certs"}, probe.Exec.Command[:5])
+	}
+}
@@ -7,12 +7,15 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	autoscaling "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	"github.com/pingcap/tiflow-operator/pkg/manager/member/prune"
 	"github.com/pingcap/tiflow-operator/pkg/status"
 )
@@ -21,15 +24,17 @@ const defaultSleepTime = 10 * time.Second
 
 type executorScaler struct {
 	ClientSet kubernetes.Interface
+	Recorder  record.EventRecorder
 	PVCPruner prune.PVCPruner
 }
 
 // NewExecutorScaler return a executorScaler
-func NewExecutorScaler(clientSet kubernetes.Interface) Scaler {
+func NewExecutorScaler(clientSet kubernetes.Interface, recorder record.EventRecorder) Scaler {
 
 	return &executorScaler{
 		ClientSet: clientSet,
-		PVCPruner: prune.NewPersistentVolumePruner(clientSet),
+		Recorder:  recorder,
+		PVCPruner: prune.NewPersistentVolumePruner(clientSet, recorder),
 	}
 }
 
@@ -92,6 +97,8 @@ func (s *executorScaler) ScaleOut(meta metav1.Object, actual *appsv1.StatefulSet
 		if err = s.SetReplicas(ctx, actual, uint(current+1)); err != nil {
 			return err
 		}
+		s.Recorder.Eventf(tc, corev1.EventTypeNormal, event.ScalingOut, "scale out tiflow-executor statefulSet %s from %d to %d",
+			stsName, current, current+1)
 
 		if err = s.WaitUntilRunning(ctx); err != nil {
 			return err
@@ -145,6 +152,8 @@ func (s *executorScaler) ScaleIn(meta metav1.Object, actual *appsv1.StatefulSet,
 		if err = s.SetReplicas(ctx, actual, uint(current-1)); err != nil {
 			return err
 		}
+		s.Recorder.Eventf(tc, corev1.EventTypeNormal, event.ScalingIn, "scale in tiflow-executor statefulSet %s from %d to %d",
+			stsName, current, current-1)
 
 		if err = s.WaitUntilHealthy(ctx, uint(current-1)); err != nil {
 			return err
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,18 +19,21 @@ import (
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
 	"github.com/pingcap/tiflow-operator/pkg/status"
 )
 
 type executorUpgrader struct {
-	client client.Client
+	client   client.Client
+	recorder record.EventRecorder
 }
 
 // NewExecutorUpgrader returns a executorUpgrader
-func NewExecutorUpgrader(cli client.Client) Upgrader {
+func NewExecutorUpgrader(cli client.Client, recorder record.EventRecorder) Upgrader {
 	return &executorUpgrader{
-		client: cli,
+		client:   cli,
+		recorder: recorder,
 	}
 }
 
@@ -41,6 +47,14 @@ func (u *executorUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSts, n
 
 	klog.Infof("start to upgrade tiflow executor [%s/%s]", ns, tcName)
 
+	rollback := newUpgradeRollback(u.client, u.recorder, tc, v1alpha1.TiFlowExecutorMemberType)
+	if held, err := rollback.held(oldSts, newSts, tc.Status.Executor.StatefulSet); held {
+		klog.Infof("tiflowCluster: [%s/%s]'s tiflow-executor upgrade is rolled back, waiting for the spec to be changed", ns, tcName)
+		return err
+	} else if err != nil {
+		return err
+	}
+
 	condition.SetFalse(v1alpha1.ExecutorSyncChecked, tc.GetClusterStatus(), metav1.Now())
 	status.Ongoing(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType,
 		fmt.Sprintf("tiflow executor [%s/%s] upgrading...", ns, tcName))
@@ -48,12 +62,7 @@ func (u *executorUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSts, n
 	if tc.ExecutorScaling() {
 		klog.Infof("TiflowCluster: [%s/%s]'s tiflow-executor is scaling, can not upgrade tiflow-executor",
 			ns, tcName)
-		_, podSpec, err := GetLastAppliedConfig(oldSts)
-		if err != nil {
-			return err
-		}
-		newSts.Spec.Template.Spec = *podSpec
-		return nil
+		return keepLastAppliedTemplate(newSts, oldSts)
 	}
 
 	tc.Status.Executor.Phase = v1alpha1.ExecutorUpgrading
@@ -66,8 +75,10 @@ func (u *executorUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSts, n
 
 	klog.Infof("CurrentRevision: %s, UpdateRevision: %s", tc.Status.Executor.StatefulSet.CurrentRevision, tc.Status.Executor.StatefulSet.UpdateRevision)
 	if tc.Status.Executor.StatefulSet.UpdateRevision == tc.Status.Executor.StatefulSet.CurrentRevision {
+		rollback.finish()
 		return nil
 	}
+	rollback.start(tc.Status.Executor.StatefulSet)
 
 	if oldSts.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType ||
 		oldSts.Spec.UpdateStrategy.RollingUpdate == nil {
@@ -77,15 +88,30 @@ func (u *executorUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSts, n
 		return nil
 	}
 
-	mngerutils.SetUpgradePartition(newSts, *oldSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	partition := *oldSts.Spec.UpdateStrategy.RollingUpdate.Partition
+	mngerutils.SetUpgradePartition(newSts, partition)
 	klog.Infof("Upgrading tiflow-executor statefulSet")
+	progress := newUpgradeProgress(tc, v1alpha1.TiFlowExecutorMemberType, tc.Spec.Executor.Upgrade, *oldSts.Spec.Replicas)
+	maxUnavailable := tc.Spec.Executor.Upgrade.GetMaxUnavailable(*oldSts.Spec.Replicas)
+	// upgrading are the pods being upgraded, and unready are the upgraded pods which are not available yet,
+	// pending is the number of pods whose upgrade is started in this round
+	var upgrading, unready []string
+	var pending int32
 	for i := *oldSts.Spec.Replicas - 1; i >= 0; i-- {
+		if int32(len(upgrading)+len(unready)) >= maxUnavailable {
+			break
+		}
 		podName := TiflowExecutorPodName(tcName, i)
 		pod := &corev1.Pod{}
 		err := u.client.Get(context.TODO(), types.NamespacedName{
 			Namespace: ns,
 			Name:      podName,
 		}, pod)
+		if errors.IsNotFound(err) {
+			// the pod deleted to be upgraded is not recreated by the statefulSet controller yet
+			upgrading = append(upgrading, podName)
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("gracefulUpgrade: failed to get pods %s for cluster [%s/%s], error: %s", podName, ns, tcName, err)
 		}
@@ -98,20 +124,95 @@ func (u *executorUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSts, n
 
 		if revision == tc.Status.Executor.StatefulSet.UpdateRevision {
 			if !podutil.IsPodReady(pod) {
-				return controller.RequeueErrorf("tiflowCluster: [%s/%s]'s upgrade tiflow-executor pod: [%s] is not ready",
-					ns, tcName, podName)
+				if rolledBack, err := rollback.timeout(newSts, pod, "is not ready"); rolledBack || err != nil {
+					return err
+				}
+				unready = append(unready, podName)
+				continue
 			}
 			// todo: Need to be modified
 			if _, exist := tc.Status.Executor.Members[podName+"."+ns]; !exist {
-				return controller.RequeueErrorf("tiflowCluster: [%s/%s]'s upgrade tiflow-executor pod: [%s] is not exist",
-					ns, tcName, podName)
+				if rolledBack, err := rollback.timeout(newSts, pod, "does not join tiflow-master"); rolledBack || err != nil {
+					return err
+				}
+				unready = append(unready, podName)
+				continue
+			}
+			progress.podUpgraded(i, pod)
+			continue
+		}
+		if i >= partition {
+			// the statefulSet controller replaces the pods behind the partition one by one,
+			// so they are deleted here to be recreated from the update revision in parallel
+			deleted, err := u.deleteOutdatedPod(tc, pod, pending)
+			if err != nil {
+				return err
 			}
+			if deleted {
+				pending++
+			}
+			upgrading = append(upgrading, podName)
 			continue
 		}
+		if hold, err := progress.hold(i); hold {
+			if pending > 0 {
+				// the pods started in this round are applied, the rest is checked again in the next round
+				break
+			}
+			return err
+		}
 		// todo: Need to re-arrange this executor's tasks in the future
-		mngerutils.SetUpgradePartition(newSts, i)
-		return nil
+		if err := checkPodDisruptionBudget(context.TODO(), u.client, tc, controller.TiflowExecutorMemberName(tcName), pending); err != nil {
+			if pending > 0 {
+				break
+			}
+			progress.wait("PodDisruptionBudget to allow upgrading pod %s", podName)
+			return err
+		}
+		u.recorder.Eventf(tc, corev1.EventTypeNormal, event.UpgradePartition, "move upgrade partition of tiflow-executor statefulSet %s to %d to upgrade pod %s",
+			newSts.GetName(), i, podName)
+		partition = i
+		mngerutils.SetUpgradePartition(newSts, partition)
+		upgrading = append(upgrading, podName)
+		pending++
 	}
 
+	if len(upgrading) > 0 {
+		progress.wait("%s to be upgraded", podsOf(upgrading))
+		return nil
+	}
+	if len(unready) > 0 {
+		progress.wait("%s to be ready and join tiflow-master", podsOf(unready))
+		return controller.RequeueErrorf("tiflowCluster: [%s/%s]'s upgraded tiflow-executor pods: %v are not ready",
+			ns, tcName, unready)
+	}
 	return nil
 }
+
+// deleteOutdatedPod deletes the pod which is not upgraded yet if the PodDisruptionBudget allows, pending pods are
+// being disrupted in this round. It returns false if the pod is left to the statefulSet controller.
+func (u *executorUpgrader) deleteOutdatedPod(tc *v1alpha1.TiflowCluster, pod *corev1.Pod, pending int32) (bool, error) {
+	if pod.DeletionTimestamp != nil {
+		return false, nil
+	}
+	ns := tc.GetNamespace()
+	if err := checkPodDisruptionBudget(context.TODO(), u.client, tc, controller.TiflowExecutorMemberName(tc.GetName()), pending); err != nil {
+		klog.Infof("tiflowCluster: [%s/%s]'s tiflow-executor pod %s is left to the statefulSet controller, %v", ns, tc.GetName(), pod.GetName(), err)
+		return false, nil
+	}
+	// the pod recreated by the statefulSet controller must not be deleted
+	err := u.client.Delete(context.TODO(), pod, client.Preconditions{UID: &pod.UID})
+	if client.IgnoreNotFound(err) != nil {
+		return false, fmt.Errorf("gracefulUpgrade: failed to delete pod %s for cluster [%s/%s], error: %v", pod.GetName(), ns, tc.GetName(), err)
+	}
+	u.recorder.Eventf(tc, corev1.EventTypeNormal, event.UpgradePod, "delete tiflow-executor pod %s to upgrade it", pod.GetName())
+	return true, nil
+}
+
+// podsOf returns the pods in a message
+func podsOf(names []string) string {
+	if len(names) == 1 {
+		return "pod " + names[0]
+	}
+	return "pods " + strings.Join(names, ", ")
+}
@@ -1 +1,378 @@
 package member
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+	"github.com/pingcap/tiflow-operator/pkg/status"
+)
+
+// newUpgradingExecutors returns a tiflow cluster whose tiflow-executor with the highest ordinal of replicas
+// has been upgraded and ready since readySince, and the statefulSet of them
+func newUpgradingExecutors(t *testing.T, replicas int32, readySince time.Time) (*v1alpha1.TiflowCluster, *appsv1.StatefulSet, []client.Object) {
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Executor: &v1alpha1.ExecutorSpec{Replicas: replicas},
+		},
+		Status: v1alpha1.TiflowClusterStatus{
+			Executor: v1alpha1.ExecutorStatus{
+				StatefulSet: &appsv1.StatefulSetStatus{CurrentRevision: "old", UpdateRevision: "new"},
+				Members:     map[string]v1alpha1.ExecutorMember{},
+			},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: controller.TiflowExecutorMemberName("demo")},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(replicas),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+		},
+	}
+	mngerutils.SetUpgradePartition(sts, replicas-1)
+	require.NoError(t, mngerutils.SetStatefulSetLastAppliedConfigAnnotation(sts))
+
+	var pods []client.Object
+	for i := int32(0); i < replicas; i++ {
+		revision := "old"
+		if i == replicas-1 {
+			revision = "new"
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns-1",
+				Name:      TiflowExecutorPodName("demo", i),
+				Labels:    map[string]string{appsv1.ControllerRevisionHashLabelKey: revision},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(readySince),
+				}},
+			},
+		}
+		pods = append(pods, pod)
+		tc.Status.Executor.Members[pod.Name+".ns-1"] = v1alpha1.ExecutorMember{Name: pod.Name + ".ns-1"}
+	}
+	return tc, sts, pods
+}
+
+func TestExecutorUpgradeStrategy(t *testing.T) {
+	type testcase struct {
+		name      string
+		upgrade   *v1alpha1.UpgradeSpec
+		requeue   bool
+		partition int32
+		message   string
+	}
+	tests := []testcase{
+		{
+			name:      "upgrade all pods",
+			partition: 2,
+			message:   "waiting for pod demo-tiflow-executor-2 to be upgraded",
+		},
+		{
+			name:      "canary of 2 pods",
+			upgrade:   &v1alpha1.UpgradeSpec{CanaryReplicas: pointer.Int32Ptr(2), Partition: pointer.Int32Ptr(3)},
+			partition: 2,
+		},
+		{
+			name:      "canary is done",
+			upgrade:   &v1alpha1.UpgradeSpec{CanaryReplicas: pointer.Int32Ptr(1)},
+			partition: 3,
+			message:   "upgraded ordinals: [3], waiting for partition to be lowered, pods with ordinal below 3 are kept in the old revision",
+		},
+		{
+			name:      "paused",
+			upgrade:   &v1alpha1.UpgradeSpec{Pause: true},
+			partition: 3,
+			message:   "waiting for upgrade to be resumed",
+		},
+		{
+			name:      "observing",
+			upgrade:   &v1alpha1.UpgradeSpec{ObservationPeriod: &metav1.Duration{Duration: time.Hour}},
+			requeue:   true,
+			partition: 3,
+			message:   "waiting for observation of upgraded pods until",
+		},
+		{
+			name:      "observed",
+			upgrade:   &v1alpha1.UpgradeSpec{ObservationPeriod: &metav1.Duration{Duration: time.Minute}},
+			partition: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tc, oldSts, pods := newUpgradingExecutors(t, 4, time.Now().Add(-10*time.Minute))
+			tc.Spec.Executor.Upgrade = test.upgrade
+			newSts := oldSts.DeepCopy()
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pods...).Build()
+
+			err := NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, oldSts, newSts)
+			if test.requeue {
+				require.True(t, controller.IsRequeueError(err))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.partition, *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+			syncType := tc.Status.Executor.SyncTypes[0]
+			require.Equal(t, v1alpha1.UpgradeType, syncType.Name)
+			require.Equal(t, v1alpha1.Ongoing, syncType.Status)
+			require.True(t, strings.Contains(syncType.Message, test.message), syncType.Message)
+		})
+	}
+}
+
+func TestExecutorParallelUpgrade(t *testing.T) {
+	type testcase struct {
+		name           string
+		maxUnavailable intstr.IntOrString
+		// disruptionsAllowed of the PodDisruptionBudget, no budget if it's nil
+		disruptionsAllowed *int32
+		unready            bool
+		requeue            bool
+		partition          int32
+		message            string
+	}
+	tests := []testcase{
+		{
+			name:           "upgrade 2 pods",
+			maxUnavailable: intstr.FromInt(2),
+			partition:      1,
+			message:        "waiting for pods demo-tiflow-executor-2, demo-tiflow-executor-1 to be upgraded",
+		},
+		{
+			name:           "upgrade 50% pods",
+			maxUnavailable: intstr.FromString("50%"),
+			partition:      1,
+			message:        "waiting for pods demo-tiflow-executor-2, demo-tiflow-executor-1 to be upgraded",
+		},
+		{
+			name:           "more than the rest",
+			maxUnavailable: intstr.FromInt(10),
+			partition:      0,
+			message:        "demo-tiflow-executor-2, demo-tiflow-executor-1, demo-tiflow-executor-0 to be upgraded",
+		},
+		{
+			name:           "an upgraded pod is not ready",
+			maxUnavailable: intstr.FromInt(2),
+			unready:        true,
+			partition:      2,
+			message:        "waiting for pod demo-tiflow-executor-2 to be upgraded",
+		},
+		{
+			name:               "limited by PodDisruptionBudget",
+			maxUnavailable:     intstr.FromInt(3),
+			disruptionsAllowed: pointer.Int32Ptr(2),
+			partition:          1,
+			message:            "waiting for pods demo-tiflow-executor-2, demo-tiflow-executor-1 to be upgraded",
+		},
+		{
+			name:               "blocked by PodDisruptionBudget",
+			maxUnavailable:     intstr.FromInt(3),
+			disruptionsAllowed: pointer.Int32Ptr(0),
+			unready:            true,
+			requeue:            true,
+			partition:          3,
+			message:            "waiting for PodDisruptionBudget to allow upgrading pod demo-tiflow-executor-2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tc, oldSts, pods := newUpgradingExecutors(t, 4, time.Now().Add(-10*time.Minute))
+			tc.Spec.Executor.Upgrade = &v1alpha1.UpgradeSpec{MaxUnavailable: &test.maxUnavailable}
+			if test.unready {
+				pods[3].(*corev1.Pod).Status.Conditions[0].Status = corev1.ConditionFalse
+			}
+			objs := pods
+			if test.disruptionsAllowed != nil {
+				objs = append(objs, &policyv1.PodDisruptionBudget{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: controller.TiflowExecutorMemberName("demo")},
+					Status: policyv1.PodDisruptionBudgetStatus{
+						DisruptionsAllowed: *test.disruptionsAllowed,
+						CurrentHealthy:     3,
+						ExpectedPods:       4,
+					},
+				})
+			}
+			newSts := oldSts.DeepCopy()
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+
+			err := NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, oldSts, newSts)
+			if test.requeue {
+				require.True(t, controller.IsRequeueError(err))
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.partition, *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+			syncType := tc.Status.Executor.SyncTypes[0]
+			require.Equal(t, v1alpha1.Ongoing, syncType.Status)
+			require.True(t, strings.Contains(syncType.Message, test.message), syncType.Message)
+		})
+	}
+}
+
+func TestExecutorParallelUpgradeReplacesPods(t *testing.T) {
+	existingPods := func(cli client.Client) []string {
+		var names []string
+		for i := int32(0); i < 4; i++ {
+			name := TiflowExecutorPodName("demo", i)
+			err := cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns-1", Name: name}, &corev1.Pod{})
+			if !errors.IsNotFound(err) {
+				require.NoError(t, err)
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	tc, oldSts, pods := newUpgradingExecutors(t, 4, time.Now().Add(-10*time.Minute))
+	maxUnavailable := intstr.FromInt(2)
+	tc.Spec.Executor.Upgrade = &v1alpha1.UpgradeSpec{MaxUnavailable: &maxUnavailable}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pods...).Build()
+	recorder := record.NewFakeRecorder(10)
+	upgrader := NewExecutorUpgrader(cli, recorder)
+
+	// the partition is moved first, no pod is replaced before it's applied
+	newSts := oldSts.DeepCopy()
+	require.NoError(t, upgrader.Upgrade(tc, oldSts, newSts))
+	require.Equal(t, int32(1), *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	require.Len(t, existingPods(cli), 4)
+
+	// the pods behind the applied partition are replaced together, rather than one by one by the statefulSet controller
+	oldSts = newSts
+	newSts = oldSts.DeepCopy()
+	require.NoError(t, upgrader.Upgrade(tc, oldSts, newSts))
+	require.Equal(t, int32(1), *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	require.Equal(t, []string{"demo-tiflow-executor-0", "demo-tiflow-executor-3"}, existingPods(cli))
+	events := strings.Join([]string{<-recorder.Events, <-recorder.Events, <-recorder.Events, <-recorder.Events}, "\n")
+	require.Contains(t, events, "UpgradePod delete tiflow-executor pod demo-tiflow-executor-2 to upgrade it")
+	require.Contains(t, events, "UpgradePod delete tiflow-executor pod demo-tiflow-executor-1 to upgrade it")
+
+	// the deleted pods are waited for until the statefulSet controller recreates them
+	oldSts = newSts
+	newSts = oldSts.DeepCopy()
+	require.NoError(t, upgrader.Upgrade(tc, oldSts, newSts))
+	require.Equal(t, int32(1), *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	require.Equal(t, []string{"demo-tiflow-executor-0", "demo-tiflow-executor-3"}, existingPods(cli))
+	require.Equal(t, v1alpha1.Ongoing, status.GetSyncStatus(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType))
+	require.Empty(t, recorder.Events)
+
+	// the PodDisruptionBudget is respected when replacing pods
+	tc, oldSts, pods = newUpgradingExecutors(t, 4, time.Now().Add(-10*time.Minute))
+	mngerutils.SetUpgradePartition(oldSts, 1)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: controller.TiflowExecutorMemberName("demo")},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1, CurrentHealthy: 4, ExpectedPods: 4},
+	}
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(pods, pdb)...).Build()
+	require.NoError(t, NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, oldSts, oldSts.DeepCopy()))
+	require.Equal(t, []string{"demo-tiflow-executor-0", "demo-tiflow-executor-1", "demo-tiflow-executor-3"}, existingPods(cli))
+}
+
+func TestExecutorUpgradeRollback(t *testing.T) {
+	oldTemplate := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "tiflow-executor", Image: "tiflow:old"}}},
+	}
+	data, err := json.Marshal(&appsv1.StatefulSet{Spec: appsv1.StatefulSetSpec{Template: oldTemplate}})
+	require.NoError(t, err)
+	revision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "old"},
+		Data:       runtime.RawExtension{Raw: data},
+	}
+
+	failedSince := time.Now().Add(-20 * time.Minute)
+	newUpgrade := func(t *testing.T) (*v1alpha1.TiflowCluster, *appsv1.StatefulSet, *appsv1.StatefulSet, client.Client) {
+		tc, oldSts, pods := newUpgradingExecutors(t, 4, failedSince)
+		tc.Spec.UpgradeTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+		tc.Status.Executor.Upgrade = &v1alpha1.UpgradeRecord{
+			FromRevision: "old",
+			FromImage:    "tiflow:old",
+			ToRevision:   "new",
+			StartTime:    metav1.NewTime(failedSince),
+		}
+		upgraded := pods[3].(*corev1.Pod)
+		upgraded.CreationTimestamp = metav1.NewTime(failedSince)
+		upgraded.Status.Conditions[0].Status = corev1.ConditionFalse
+		oldSts.Spec.Template.Spec.Containers = []corev1.Container{{Name: "tiflow-executor", Image: "tiflow:new"}}
+		require.NoError(t, mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSts))
+		newSts := oldSts.DeepCopy()
+		cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(append(pods, revision)...).Build()
+		return tc, oldSts, newSts, cli
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		tc, oldSts, newSts, cli := newUpgrade(t)
+		tc.Annotations = map[string]string{label.AnnDisableAutoRollbackKey: label.AnnDisableAutoRollbackVal}
+		err := NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, oldSts, newSts)
+		require.True(t, controller.IsRequeueError(err))
+		require.Equal(t, "tiflow:new", newSts.Spec.Template.Spec.Containers[0].Image)
+		require.False(t, tc.Status.Executor.Upgrade.RolledBack())
+	})
+
+	tc, oldSts, newSts, cli := newUpgrade(t)
+	generated := newSts.DeepCopy()
+	require.NoError(t, NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, oldSts, newSts))
+	require.Equal(t, oldTemplate, newSts.Spec.Template)
+	require.Equal(t, int32(0), *newSts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	require.True(t, tc.Status.Executor.Upgrade.RolledBack())
+	syncType := tc.Status.Executor.SyncTypes[0]
+	require.Equal(t, v1alpha1.Failed, syncType.Status)
+	require.True(t, strings.Contains(syncType.Message, "rolled back to revision old (image tiflow:old)"), syncType.Message)
+
+	// the rolled back template is kept, and the failed pod is replaced once the rollback is observed
+	require.NoError(t, mngerutils.SetStatefulSetLastAppliedConfigAnnotation(newSts))
+	tc.Status.Executor.StatefulSet.UpdateRevision = "old"
+	require.NoError(t, NewExecutorUpgrader(cli, record.NewFakeRecorder(10)).Upgrade(tc, newSts, generated))
+	require.Equal(t, oldTemplate, generated.Spec.Template)
+	require.Equal(t, v1alpha1.Failed, tc.Status.Executor.SyncTypes[0].Status)
+	err = cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns-1", Name: TiflowExecutorPodName("demo", 3)}, &corev1.Pod{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestExecutorRollingRestart(t *testing.T) {
+	_, oldSts, _ := newUpgradingExecutors(t, 4, time.Now())
+	oldSts.Spec.Template.Annotations = map[string]string{label.AnnRestartedAtKey: "2022-10-01T00:00:00Z"}
+	require.NoError(t, mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSts))
+
+	newSts := oldSts.DeepCopy()
+	require.True(t, templateEqual(newSts, oldSts))
+
+	// restarting goes through the upgrader
+	newSts.Spec.Template.Annotations[label.AnnRestartedAtKey] = "2022-10-02T00:00:00Z"
+	require.False(t, templateEqual(newSts, oldSts))
+
+	// the restart is deferred by scaling
+	require.NoError(t, keepLastAppliedTemplate(newSts, oldSts))
+	require.Equal(t, "2022-10-01T00:00:00Z", newSts.Spec.Template.Annotations[label.AnnRestartedAtKey])
+	require.True(t, templateEqual(newSts, oldSts))
+}
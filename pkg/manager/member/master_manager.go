@@ -7,11 +7,13 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,6 +23,7 @@ import (
 	"github.com/pingcap/tiflow-operator/pkg/component"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	"github.com/pingcap/tiflow-operator/pkg/manager"
 	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
 	"github.com/pingcap/tiflow-operator/pkg/status"
@@ -32,16 +35,20 @@ const (
 )
 
 type masterMemberManager struct {
-	cli      client.Client
-	scaler   Scaler
-	upgrader Upgrader
+	cli       client.Client
+	clientSet kubernetes.Interface
+	recorder  record.EventRecorder
+	scaler    Scaler
+	upgrader  Upgrader
 }
 
-func NewMasterMemberManager(cli client.Client, clientSet kubernetes.Interface) manager.TiflowManager {
+func NewMasterMemberManager(cli client.Client, clientSet kubernetes.Interface, recorder record.EventRecorder) manager.TiflowManager {
 	return &masterMemberManager{
-		cli:      cli,
-		scaler:   NewMasterScaler(cli, clientSet),
-		upgrader: NewMasterUpgrader(cli),
+		cli:       cli,
+		clientSet: clientSet,
+		recorder:  recorder,
+		scaler:    NewMasterScaler(cli, clientSet, recorder),
+		upgrader:  NewMasterUpgrader(cli, recorder),
 	}
 }
 
@@ -64,8 +71,110 @@ func (m *masterMemberManager) Sync(ctx context.Context, tc *pingcapcomv1alpha1.T
 		return err
 	}
 
+	// Sync tiflow-master Ingress
+	if err := m.syncMasterIngressForTiflowCluster(ctx, tc); err != nil {
+		return err
+	}
+
+	// Sync tiflow-master PodDisruptionBudget
+	if err := syncMasterPodDisruptionBudget(ctx, m.cli, tc); err != nil {
+		return err
+	}
+
+	// Suspend tiflow-master StatefulSet, services and configmap are kept
+	if tc.IsPaused() {
+		return m.suspendMasterStatefulSet(ctx, tc)
+	}
+
 	// Sync tiflow-master StatefulSet
-	return m.syncMasterStatefulSetForTiflowCluster(ctx, tc)
+	if err := m.syncMasterStatefulSetForTiflowCluster(ctx, tc); err != nil {
+		return err
+	}
+
+	// Process the operations requested by the annotations of tiflow-master pods
+	return syncPodOperations(ctx, m.cli, m.recorder, tc, pingcapcomv1alpha1.TiFlowMasterMemberType)
+}
+
+// suspendMasterStatefulSet scales the tiflow-master statefulSet to zero, it should be called after all executors are suspended.
+func (m *masterMemberManager) suspendMasterStatefulSet(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	sts := &apps.StatefulSet{}
+	err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowMasterMemberName(tcName),
+	}, sts)
+	if errors.IsNotFound(err) {
+		// nothing to scale in, the statefulSet is never created or has been deleted
+		completeSuspend(tc, pingcapcomv1alpha1.TiFlowMasterMemberType, "master suspending completed")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("suspendMaster: failed to get sts %s for cluster %s/%s, error: %v", controller.TiflowMasterMemberName(tcName), ns, tcName, err)
+	}
+
+	tc.Status.Master.StatefulSet = sts.Status.DeepCopy()
+	condition.SetFalse(pingcapcomv1alpha1.MasterSyncChecked, tc.GetClusterStatus(), metav1.Now())
+
+	removed, err := scaleStatefulSetToZero(ctx, m.clientSet, sts)
+	if err != nil {
+		status.Failed(pingcapcomv1alpha1.SuspendType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+			fmt.Sprintf("tiflow master [%s/%s] suspending failed", ns, tcName))
+		return err
+	}
+	if !removed {
+		status.Ongoing(pingcapcomv1alpha1.SuspendType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+			fmt.Sprintf("tiflow master [%s/%s] suspending...", ns, tcName))
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-master is suspending, %d pods left", ns, tcName, sts.Status.Replicas)
+	}
+
+	completeSuspend(tc, pingcapcomv1alpha1.TiFlowMasterMemberType, "master suspending completed")
+	return nil
+}
+
+// Delete scales the tiflow-master statefulSet to zero, it should be called after all executors are removed.
+// The statefulSet, services and configmap are left to the garbage collector.
+func (m *masterMemberManager) Delete(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.Spec.Master == nil {
+		return nil
+	}
+
+	sts := &apps.StatefulSet{}
+	err := m.cli.Get(ctx, types.NamespacedName{
+		Namespace: ns,
+		Name:      controller.TiflowMasterMemberName(tcName),
+	}, sts)
+	if errors.IsNotFound(err) {
+		status.Completed(pingcapcomv1alpha1.DeleteType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+			"master deleting completed")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleteMaster: failed to get sts %s for cluster %s/%s, error: %v", controller.TiflowMasterMemberName(tcName), ns, tcName, err)
+	}
+
+	klog.Infof("start to delete tiflow master [%s/%s]", ns, tcName)
+	condition.SetFalse(pingcapcomv1alpha1.MasterSyncChecked, tc.GetClusterStatus(), metav1.Now())
+	status.Ongoing(pingcapcomv1alpha1.DeleteType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+		fmt.Sprintf("tiflow master [%s/%s] deleting...", ns, tcName))
+
+	removed, err := scaleStatefulSetToZero(ctx, m.clientSet, sts)
+	if err != nil {
+		status.Failed(pingcapcomv1alpha1.DeleteType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+			fmt.Sprintf("tiflow master [%s/%s] deleting failed", ns, tcName))
+		return err
+	}
+	if !removed {
+		return controller.RequeueErrorf("tiflow cluster: [%s/%s]'s tiflow-master is deleting, %d pods left", ns, tcName, sts.Status.Replicas)
+	}
+
+	status.Completed(pingcapcomv1alpha1.DeleteType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+		"master deleting completed")
+	return nil
 }
 
 func getMasterConfigMap(tc *pingcapcomv1alpha1.TiflowCluster) (*corev1.ConfigMap, error) {
@@ -185,6 +294,56 @@ func (m *masterMemberManager) syncMasterServiceForTiflowCluster(ctx context.Cont
 	return nil
 }
 
+// syncMasterIngressForTiflowCluster creates the Ingress of tiflow-master service if spec.master.ingress is set,
+// and removes the Ingress once it's unset.
+func (m *masterMemberManager) syncMasterIngressForTiflowCluster(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+	ingressName := controller.TiflowMasterMemberName(tcName)
+
+	oldIngress := &networkingv1.Ingress{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: ingressName}, oldIngress)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("syncMasterIngressForTiflowCluster: failed to get ingress %s for cluster %s/%s, error: %s", ingressName, ns, tcName, err)
+	}
+	exist := err == nil
+
+	if tc.Spec.Master.Ingress == nil {
+		if !exist || !metav1.IsControlledBy(oldIngress, tc) {
+			return nil
+		}
+		klog.Infof("tiflow cluster: [%s/%s] removing ingress of tiflow-master", ns, tcName)
+		return client.IgnoreNotFound(m.cli.Delete(ctx, oldIngress))
+	}
+
+	newIngress := getNewMasterIngressForTiflowCluster(tc)
+	if !exist {
+		if err = controller.SetIngressLastAppliedConfigAnnotation(newIngress); err != nil {
+			return err
+		}
+		klog.Infof("tiflow cluster: [%s/%s] creating ingress of tiflow-master", ns, tcName)
+		return m.cli.Create(ctx, newIngress)
+	}
+
+	equal, err := controller.IngressEqual(newIngress, oldIngress)
+	if err != nil {
+		return err
+	}
+	if equal {
+		return nil
+	}
+
+	// only the annotations of the TiflowCluster are recorded, the ones added by others are kept
+	if err = controller.SetIngressLastAppliedConfigAnnotation(newIngress); err != nil {
+		return err
+	}
+	ingress := oldIngress.DeepCopy()
+	ingress.Labels = newIngress.Labels
+	ingress.Annotations = controller.MergeIngressAnnotations(newIngress, oldIngress)
+	ingress.Spec = newIngress.Spec
+	return m.cli.Update(ctx, ingress)
+}
+
 func (m *masterMemberManager) syncMasterHeadlessServiceForTiflowCluster(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -268,11 +427,25 @@ func (m *masterMemberManager) syncMasterStatefulSetForTiflowCluster(ctx context.
 				fmt.Sprintf("create master cluster [%s/%s] failed", ns, tcName))
 			return err
 		}
+		m.recorder.Eventf(tc, corev1.EventTypeNormal, event.SuccessfulCreate, "create tiflow-master statefulSet %s with %d replicas",
+			newMasterSet.GetName(), *newMasterSet.Spec.Replicas)
 
 		tc.Status.Master.StatefulSet = &apps.StatefulSetStatus{}
 		return nil
 	}
 
+	// Resume the suspended cluster before anything else, the status can't be synced until masters are back
+	if NeedResume(tc, pingcapcomv1alpha1.TiFlowMasterMemberType) {
+		condition.SetFalse(pingcapcomv1alpha1.MasterSyncChecked, tc.GetClusterStatus(), metav1.Now())
+		status.Remove(pingcapcomv1alpha1.SuspendType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType)
+		status.Ongoing(pingcapcomv1alpha1.ResumeType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+			fmt.Sprintf("start to resume master cluster [%s/%s]", ns, tcName))
+		return scaleStatefulSet(ctx, m.clientSet, oldMasterSet, *newMasterSet.Spec.Replicas)
+	}
+
+	keepBlockedVersion(tc, pingcapcomv1alpha1.TiFlowMasterMemberType, newMasterSet, oldMasterSet)
+	keepPrestopImage(newMasterSet, oldMasterSet)
+
 	if condition.False(pingcapcomv1alpha1.MasterSyncChecked, tc.GetClusterConditions()) {
 		// Force update takes precedence over scaling because force upgrade won't take effect when cluster gets stuck at scaling
 		if NeedForceUpgrade(tc.Annotations) {
@@ -312,8 +485,11 @@ func (m *masterMemberManager) syncMasterStatefulSetForTiflowCluster(ctx context.
 
 	if !templateEqual(newMasterSet, oldMasterSet) || tc.Status.Master.Phase == pingcapcomv1alpha1.MasterUpgrading {
 		if err := m.upgrader.Upgrade(tc, oldMasterSet, newMasterSet); err != nil {
-			status.Failed(pingcapcomv1alpha1.UpgradeType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
-				fmt.Sprintf("tiflow master [%s/%s] upgrading failed", ns, tcName))
+			// a RequeueError means the upgrade is waiting, whose reason is reported by the upgrader
+			if !controller.IsRequeueError(err) {
+				status.Failed(pingcapcomv1alpha1.UpgradeType, tc.GetClusterStatus(), pingcapcomv1alpha1.TiFlowMasterMemberType,
+					fmt.Sprintf("tiflow master [%s/%s] upgrading failed", ns, tcName))
+			}
 
 			return err
 		}
@@ -337,6 +513,7 @@ func getNewMasterSetForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster, cm *c
 		annoMount,
 		{Name: "config", ReadOnly: true, MountPath: "/etc/tiflow-master"},
 		{Name: "startup-script", ReadOnly: true, MountPath: "/usr/local/bin"},
+		prestopVolumeMount(),
 	}
 	volMounts = append(volMounts, tc.Spec.Master.AdditionalVolumeMounts...)
 
@@ -348,6 +525,7 @@ func getNewMasterSetForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster, cm *c
 
 	vols := []corev1.Volume{
 		annoVolume,
+		prestopVolume(),
 		{Name: "config",
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
@@ -418,6 +596,9 @@ func getNewMasterSetForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster, cm *c
 		VolumeMounts: volMounts,
 		Resources:    controller.ContainerResource(tc.Spec.Master.ResourceRequirements),
 	}
+	masterContainer.ReadinessProbe, masterContainer.LivenessProbe, masterContainer.StartupProbe =
+		getComponentProbes(baseMasterSpec, masterPort, tc.IsClusterTLSEnabled())
+	masterContainer.Lifecycle = masterPrestop(tc, baseMasterSpec)
 	env := []corev1.EnvVar{
 		{
 			Name: "NAMESPACE",
@@ -456,7 +637,7 @@ func getNewMasterSetForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster, cm *c
 	masterContainer.EnvFrom = baseMasterSpec.EnvFrom()
 	podSpec.Volumes = append(vols, baseMasterSpec.AdditionalVolumes()...)
 	podSpec.Containers = append([]corev1.Container{masterContainer}, baseMasterSpec.AdditionalContainers()...)
-	var initContainers []corev1.Container // no default initContainers now
+	initContainers := []corev1.Container{prestopInitContainer()}
 	podSpec.InitContainers = append(initContainers, baseMasterSpec.InitContainers()...)
 
 	updateStrategy := apps.StatefulSetUpdateStrategy{}
@@ -560,6 +741,58 @@ func (m *masterMemberManager) getNewMasterServiceForTiflowCluster(tc *pingcapcom
 	return masterSvc
 }
 
+func getNewMasterIngressForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster) *networkingv1.Ingress {
+	ns := tc.Namespace
+	tcName := tc.Name
+	instanceName := tc.GetInstanceName()
+	ingressSpec := tc.Spec.Master.Ingress
+	pathType := networkingv1.PathTypePrefix
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiflowMasterMemberName(tcName),
+			Namespace:       ns,
+			Labels:          label.New().Instance(instanceName).TiflowMaster().Labels(),
+			Annotations:     util.CopyStringMap(ingressSpec.Annotations),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ingressSpec.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: ingressSpec.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: controller.TiflowMasterMemberName(tcName),
+											Port: networkingv1.ServiceBackendPort{Number: masterPort},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if ingressSpec.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{ingressSpec.Host},
+				SecretName: ingressSpec.TLSSecretName,
+			},
+		}
+	}
+	return ingress
+}
+
 func getNewMasterHeadlessServiceForTiflowCluster(tc *pingcapcomv1alpha1.TiflowCluster) *corev1.Service {
 	ns := tc.Namespace
 	tcName := tc.Name
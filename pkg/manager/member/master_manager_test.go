@@ -0,0 +1,244 @@
+package member
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+)
+
+func TestMasterIngress(t *testing.T) {
+	tc := &pingcapcomv1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: pingcapcomv1alpha1.TiflowClusterSpec{
+			Master: &pingcapcomv1alpha1.MasterSpec{
+				Ingress: &pingcapcomv1alpha1.IngressSpec{
+					Host:             "tiflow.example.com",
+					TLSSecretName:    "tiflow-tls",
+					IngressClassName: pointer.StringPtr("nginx"),
+					Annotations:      map[string]string{"foo": "bar"},
+				},
+			},
+		},
+	}
+
+	ingress := getNewMasterIngressForTiflowCluster(tc)
+	require.Equal(t, "demo-tiflow-master", ingress.Name)
+	require.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+	require.Equal(t, "tiflow.example.com", ingress.Spec.Rules[0].Host)
+	backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+	require.Equal(t, "demo-tiflow-master", backend.Name)
+	require.Equal(t, int32(masterPort), backend.Port.Number)
+	require.Equal(t, []string{"tiflow.example.com"}, ingress.Spec.TLS[0].Hosts)
+	require.Equal(t, "tiflow-tls", ingress.Spec.TLS[0].SecretName)
+
+	old := ingress.DeepCopy()
+	require.NoError(t, controller.SetIngressLastAppliedConfigAnnotation(old))
+	equal, err := controller.IngressEqual(getNewMasterIngressForTiflowCluster(tc), old)
+	require.NoError(t, err)
+	require.True(t, equal)
+
+	tc.Spec.Master.Ingress.Annotations["foo"] = "baz"
+	equal, err = controller.IngressEqual(getNewMasterIngressForTiflowCluster(tc), old)
+	require.NoError(t, err)
+	require.False(t, equal)
+
+	tc.Spec.Master.Ingress.Annotations["foo"] = "bar"
+	tc.Spec.Master.Ingress.Host = "tiflow.example.org"
+	equal, err = controller.IngressEqual(getNewMasterIngressForTiflowCluster(tc), old)
+	require.NoError(t, err)
+	require.False(t, equal)
+
+	// removing an annotation is applied, while the annotations added by others are kept
+	tc.Spec.Master.Ingress.Host = "tiflow.example.com"
+	old.Annotations["kubernetes.io/ingress.class"] = "nginx"
+	delete(tc.Spec.Master.Ingress.Annotations, "foo")
+	newIngress := getNewMasterIngressForTiflowCluster(tc)
+	equal, err = controller.IngressEqual(newIngress, old)
+	require.NoError(t, err)
+	require.False(t, equal)
+
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(old).Build()
+	m := &masterMemberManager{cli: cli}
+	require.NoError(t, m.syncMasterIngressForTiflowCluster(context.TODO(), tc))
+	updated := &networkingv1.Ingress{}
+	require.NoError(t, cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-master"}, updated))
+	require.NotContains(t, updated.Annotations, "foo")
+	require.Equal(t, "nginx", updated.Annotations["kubernetes.io/ingress.class"])
+	equal, err = controller.IngressEqual(getNewMasterIngressForTiflowCluster(tc), updated)
+	require.NoError(t, err)
+	require.True(t, equal)
+}
+
+func TestMasterProbes(t *testing.T) {
+	tc := &pingcapcomv1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: pingcapcomv1alpha1.TiflowClusterSpec{
+			Master: &pingcapcomv1alpha1.MasterSpec{
+				ComponentSpec: pingcapcomv1alpha1.ComponentSpec{
+					ReadinessProbe: &corev1.Probe{InitialDelaySeconds: 10, FailureThreshold: 6},
+					LivenessProbe: &corev1.Probe{
+						ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(masterPort)}},
+					},
+				},
+			},
+			TLSCluster: pointer.BoolPtr(true),
+		},
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "demo-tiflow-master"}}
+	sts, err := getNewMasterSetForTiflowCluster(tc, cm)
+	require.NoError(t, err)
+	container := sts.Spec.Template.Spec.Containers[0]
+
+	// kubelet can't present the client certificates, so the probes are run by the operator binary in the pod
+	readiness := container.ReadinessProbe
+	require.Nil(t, readiness.HTTPGet)
+	// readiness doesn't depend on the leader, so members stay ready during elections
+	require.Equal(t, []string{"/var/lib/tiflow-operator/manager", "probe",
+		"--port=10240", "--path=/metrics", "--tls-cert-dir=/var/lib/tiflow-cluster-certs"}, readiness.Exec.Command)
+	require.Equal(t, int32(10), readiness.InitialDelaySeconds)
+	require.Equal(t, int32(6), readiness.FailureThreshold)
+	require.Equal(t, int32(5), readiness.PeriodSeconds)
+
+	require.Nil(t, container.LivenessProbe.Exec)
+	require.Equal(t, masterPort, container.LivenessProbe.TCPSocket.Port.IntValue())
+	// the container isn't restarted while the pod is in debug mode
+	require.Equal(t, []string{"/var/lib/tiflow-operator/manager", "probe",
+		"--port=10240", "--path=/metrics", "--tls-cert-dir=/var/lib/tiflow-cluster-certs",
+		"--annotations-file=/etc/podinfo/annotations"}, container.StartupProbe.Exec.Command)
+	require.Equal(t, int32(60), container.StartupProbe.FailureThreshold)
+
+	tc.Spec.TLSCluster = nil
+	sts, err = getNewMasterSetForTiflowCluster(tc, cm)
+	require.NoError(t, err)
+	// kubelet requests the status endpoint without TLS
+	readiness = sts.Spec.Template.Spec.Containers[0].ReadinessProbe
+	require.Nil(t, readiness.Exec)
+	require.Equal(t, "/metrics", readiness.HTTPGet.Path)
+	require.Equal(t, masterPort, readiness.HTTPGet.Port.IntValue())
+	require.Equal(t, corev1.URISchemeHTTP, readiness.HTTPGet.Scheme)
+}
+
+func TestKeepPrestopImage(t *testing.T) {
+	defer func(image string) { controller.DiscoveryImage = image }(controller.DiscoveryImage)
+	controller.DiscoveryImage = "tiflow-operator:v1"
+	tc := &pingcapcomv1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: pingcapcomv1alpha1.TiflowClusterSpec{
+			Version: "v6.5.0",
+			Master:  &pingcapcomv1alpha1.MasterSpec{Replicas: 3},
+		},
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "demo-tiflow-master"}}
+	oldSts, err := getNewMasterSetForTiflowCluster(tc, cm)
+	require.NoError(t, err)
+	require.NoError(t, mngerutils.SetStatefulSetLastAppliedConfigAnnotation(oldSts))
+
+	// upgrading the operator alone doesn't roll the pods
+	controller.DiscoveryImage = "tiflow-operator:v2"
+	newSts, err := getNewMasterSetForTiflowCluster(tc, cm)
+	require.NoError(t, err)
+	keepPrestopImage(newSts, oldSts)
+	require.Equal(t, "tiflow-operator:v1", newSts.Spec.Template.Spec.InitContainers[0].Image)
+	require.True(t, templateEqual(newSts, oldSts))
+
+	// the binary is refreshed together with other changes
+	tc.Spec.Version = "v6.5.1"
+	newSts, err = getNewMasterSetForTiflowCluster(tc, cm)
+	require.NoError(t, err)
+	keepPrestopImage(newSts, oldSts)
+	require.Equal(t, "tiflow-operator:v2", newSts.Spec.Template.Spec.InitContainers[0].Image)
+}
+
+func TestUnhealthyMasterMembers(t *testing.T) {
+	tc := &pingcapcomv1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"}}
+	tc.Status.Master.Members = map[string]pingcapcomv1alpha1.MasterMember{
+		"demo-tiflow-master-0.ns-1": {Health: true},
+		"demo-tiflow-master-1.ns-1": {Health: false},
+	}
+	tc.Status.Master.PeerMembers = map[string]pingcapcomv1alpha1.MasterMember{
+		// being scaled in
+		"demo-tiflow-master-3.ns-1": {Health: false},
+		"peer-tiflow-master-0.ns-2": {Health: false},
+	}
+
+	// master-2 hasn't joined yet
+	require.Equal(t, []string{"demo-tiflow-master-1", "demo-tiflow-master-2", "peer-tiflow-master-0.ns-2"},
+		unhealthyMasterMembers(tc, 3, ""))
+	require.Equal(t, []string{"demo-tiflow-master-2", "peer-tiflow-master-0.ns-2"},
+		unhealthyMasterMembers(tc, 3, "demo-tiflow-master-1"))
+
+	tc.Status.Master.PeerMembers = nil
+	require.Empty(t, unhealthyMasterMembers(tc, 1, ""))
+
+	// scaling goes on with a dead member as long as the quorum is kept
+	require.NoError(t, checkMasterQuorum(tc, 2, 1))
+	require.NoError(t, checkMasterQuorum(tc, 1, 0))
+	require.Error(t, checkMasterQuorum(tc, 2, 0))
+	tc.Status.Master.Members["demo-tiflow-master-2.ns-1"] = pingcapcomv1alpha1.MasterMember{Health: false}
+	require.Error(t, checkMasterQuorum(tc, 3, 1))
+}
+
+// newScaleRecorder returns a clientSet recording the replicas which statefulSets are scaled to
+func newScaleRecorder() (*kubefake.Clientset, map[string]int32) {
+	scaled := map[string]int32{}
+	clientSet := kubefake.NewSimpleClientset()
+	clientSet.PrependReactor("update", "statefulsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := action.(k8stesting.UpdateAction).GetObject().(*autoscaling.Scale)
+		scaled[scale.Name] = scale.Spec.Replicas
+		return true, scale, nil
+	})
+	return clientSet, scaled
+}
+
+func TestMasterDelete(t *testing.T) {
+	ctx := context.Background()
+	tc := &pingcapcomv1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec:       pingcapcomv1alpha1.TiflowClusterSpec{Master: &pingcapcomv1alpha1.MasterSpec{}},
+	}
+	sts := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo-tiflow-master"},
+		Spec:       apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+		Status:     apps.StatefulSetStatus{Replicas: 3},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(sts).Build()
+	clientSet, scaled := newScaleRecorder()
+	m := NewMasterMemberManager(cli, clientSet, record.NewFakeRecorder(10))
+
+	// scaled to zero, and waited for the pods to be removed
+	err := m.Delete(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+	require.Equal(t, map[string]int32{"demo-tiflow-master": 0}, scaled)
+
+	sts.Spec.Replicas = pointer.Int32Ptr(0)
+	require.NoError(t, cli.Update(ctx, sts))
+	err = m.Delete(ctx, tc)
+	require.True(t, controller.IsRequeueError(err))
+
+	sts.Status.Replicas = 0
+	require.NoError(t, cli.Update(ctx, sts))
+	require.NoError(t, m.Delete(ctx, tc))
+	require.Equal(t, pingcapcomv1alpha1.Completed, tc.Status.Master.SyncTypes[len(tc.Status.Master.SyncTypes)-1].Status)
+}
@@ -7,28 +7,32 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	autoscaling "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	"github.com/pingcap/tiflow-operator/pkg/status"
-	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 )
 
 type masterScaler struct {
 	cli       client.Client
 	clientSet kubernetes.Interface
+	recorder  record.EventRecorder
 }
 
 // NewMasterScaler returns a DMScaler
-func NewMasterScaler(cli client.Client, clientSet kubernetes.Interface) Scaler {
+func NewMasterScaler(cli client.Client, clientSet kubernetes.Interface, recorder record.EventRecorder) Scaler {
 	return &masterScaler{
 		cli:       cli,
 		clientSet: clientSet,
+		recorder:  recorder,
 	}
 }
 
@@ -59,7 +63,7 @@ func (s masterScaler) ScaleOut(meta metav1.Object, actual *apps.StatefulSet, des
 	status.Ongoing(v1alpha1.ScaleOutType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
 		fmt.Sprintf("tiflow master [%s/%s] sacling out...", ns, tcName))
 	defer func() {
-		if err != nil {
+		if err != nil && !controller.IsRequeueError(err) {
 			status.Failed(v1alpha1.ScaleOutType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
 				fmt.Sprintf("tiflow master [%s/%s] scaling out failed", ns, tcName))
 		}
@@ -76,9 +80,15 @@ func (s masterScaler) ScaleOut(meta metav1.Object, actual *apps.StatefulSet, des
 		klog.Infof("scaling out statefulSet %s of master, current: %d, desired: %d",
 			stsName, current, current+1)
 
+		if err = checkMasterQuorum(tc, current, 1); err != nil {
+			return err
+		}
+
 		if err = s.SetReplicas(ctx, actual, uint(current+1)); err != nil {
 			return err
 		}
+		s.recorder.Eventf(tc, corev1.EventTypeNormal, event.ScalingOut, "scale out tiflow-master statefulSet %s from %d to %d",
+			stsName, current, current+1)
 
 		if err = s.WaitUntilRunning(ctx); err != nil {
 			return err
@@ -112,7 +122,7 @@ func (s masterScaler) ScaleIn(meta metav1.Object, actual *apps.StatefulSet, desi
 	status.Ongoing(v1alpha1.ScaleInType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
 		fmt.Sprintf("tiflow master [%s/%s] sacling in...", ns, tcName))
 	defer func() {
-		if err != nil {
+		if err != nil && !controller.IsRequeueError(err) {
 			status.Failed(v1alpha1.ScaleInType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
 				fmt.Sprintf("tiflow master [%s/%s] scaling in failed", ns, tcName))
 		}
@@ -129,6 +139,10 @@ func (s masterScaler) ScaleIn(meta metav1.Object, actual *apps.StatefulSet, desi
 		klog.Infof("scaling in statefulSet %s of master, current: %d, desired: %d",
 			stsName, current, current-1)
 
+		if err = checkMasterQuorum(tc, current-1, 0); err != nil {
+			return err
+		}
+
 		if err = s.EvictLeader(tc, current-1); err != nil {
 			return err
 		}
@@ -136,6 +150,8 @@ func (s masterScaler) ScaleIn(meta metav1.Object, actual *apps.StatefulSet, desi
 		if err = s.SetReplicas(ctx, actual, uint(current-1)); err != nil {
 			return err
 		}
+		s.recorder.Eventf(tc, corev1.EventTypeNormal, event.ScalingIn, "scale in tiflow-master statefulSet %s from %d to %d",
+			stsName, current, current-1)
 
 		if err = s.WaitUntilHealthy(ctx, uint(current-1)); err != nil {
 			return err
@@ -151,6 +167,20 @@ func (s masterScaler) ScaleIn(meta metav1.Object, actual *apps.StatefulSet, desi
 	return nil
 }
 
+// checkMasterQuorum returns a RequeueError unless the healthy tiflow-masters kept by scaling, together with the joining
+// ones, are the majority of the members after scaling. So a dead member doesn't block scaling, as long as the cluster
+// keeps its quorum. The health is refreshed by status syncing, so a new member is only checked by the next round.
+func checkMasterQuorum(tc *v1alpha1.TiflowCluster, replicas, joining int32) error {
+	unhealthy := unhealthyMasterMembers(tc, replicas, "")
+	kept := replicas + int32(len(peerMasterMembers(tc)))
+	healthy := kept - int32(len(unhealthy)) + joining
+	if size := kept + joining; healthy*2 <= size {
+		return controller.RequeueErrorf("tiflow cluster [%s/%s]'s tiflow-master members %v are not healthy, only %d of %d members would be healthy, can't scale now",
+			tc.GetNamespace(), tc.GetName(), unhealthy, healthy, size)
+	}
+	return nil
+}
+
 func (s *masterScaler) EvictLeader(tc *v1alpha1.TiflowCluster, ordinal int32) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
@@ -162,11 +192,14 @@ func (s *masterScaler) EvictLeader(tc *v1alpha1.TiflowCluster, ordinal int32) er
 		if tc.Status.Master.Leader.ClientURL == memberName {
 			klog.Infof("tiflow cluster [%s/%s]'s tiflow-master pod [%s/%s] is transferring tiflow-master leader",
 				ns, tcName, ns, memberName)
-			masterPeerClient := tiflowapi.GetMasterClient(s.cli, ns, tcName, memberName, tc.IsClusterTLSEnabled())
+			masterPeerClient := getMasterClient(s.cli, ns, tcName, memberName, tc.IsClusterTLSEnabled())
 			err := masterPeerClient.EvictLeader()
 			if err != nil {
+				s.recorder.Eventf(tc, corev1.EventTypeWarning, event.FailedEvictLeader, "failed to evict leader of tiflow-master %s before scaling in, error: %v",
+					memberName, err)
 				return err
 			}
+			s.recorder.Eventf(tc, corev1.EventTypeNormal, event.EvictLeader, "evict leader of tiflow-master %s before scaling in", memberName)
 			return controller.RequeueErrorf("tiflow cluster [%s/%s]'s tiflow-master pod [%s/%s] is transferring tiflow-master leader, can't scale-in now",
 				ns, tcName, ns, memberName)
 		}
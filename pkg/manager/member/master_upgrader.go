@@ -9,6 +9,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,19 +17,21 @@ import (
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
 	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
 	"github.com/pingcap/tiflow-operator/pkg/status"
-	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
 )
 
 type masterUpgrader struct {
-	cli client.Client
+	cli      client.Client
+	recorder record.EventRecorder
 }
 
 // NewMasterUpgrader returns a masterUpgrader
-func NewMasterUpgrader(cli client.Client) Upgrader {
+func NewMasterUpgrader(cli client.Client, recorder record.EventRecorder) Upgrader {
 	return &masterUpgrader{
-		cli: cli,
+		cli:      cli,
+		recorder: recorder,
 	}
 }
 
@@ -42,18 +45,21 @@ func (u *masterUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSet *app
 
 	klog.Infof("start to upgrade tiflow master [%s/%s]", ns, tcName)
 
+	rollback := newUpgradeRollback(u.cli, u.recorder, tc, v1alpha1.TiFlowMasterMemberType)
+	if held, err := rollback.held(oldSet, newSet, tc.Status.Master.StatefulSet); held {
+		klog.Infof("tiflowcluster: [%s/%s]'s tiflow-master upgrade is rolled back, waiting for the spec to be changed", ns, tcName)
+		return err
+	} else if err != nil {
+		return err
+	}
+
 	condition.SetFalse(v1alpha1.MasterSyncChecked, tc.GetClusterStatus(), metav1.Now())
 	status.Ongoing(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType,
 		fmt.Sprintf("tiflow master [%s/%s] upgrading...", ns, tcName))
 
 	if tc.MasterScaling() {
 		klog.Infof("TiflowCluster: [%s/%s]'s tiflow-master is scaling, can not upgrade tiflow-master", ns, tcName)
-		_, podSpec, err := GetLastAppliedConfig(oldSet)
-		if err != nil {
-			return err
-		}
-		newSet.Spec.Template.Spec = *podSpec
-		return nil
+		return keepLastAppliedTemplate(newSet, oldSet)
 	}
 
 	tc.Status.Master.Phase = v1alpha1.MasterUpgrading
@@ -62,8 +68,10 @@ func (u *masterUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSet *app
 	}
 
 	if tc.Status.Master.StatefulSet.UpdateRevision == tc.Status.Master.StatefulSet.CurrentRevision {
+		rollback.finish()
 		return nil
 	}
+	rollback.start(tc.Status.Master.StatefulSet)
 
 	if oldSet.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType || oldSet.Spec.UpdateStrategy.RollingUpdate == nil {
 		// Manually bypass tiflow-operator to modify statefulset directly, such as modify tiflow-master statefulset's RollingUpdate straregy to OnDelete strategy,
@@ -76,6 +84,7 @@ func (u *masterUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSet *app
 	}
 
 	mngerutils.SetUpgradePartition(newSet, *oldSet.Spec.UpdateStrategy.RollingUpdate.Partition)
+	progress := newUpgradeProgress(tc, v1alpha1.TiFlowMasterMemberType, tc.Spec.Master.Upgrade, *oldSet.Spec.Replicas)
 	for i := *oldSet.Spec.Replicas - 1; i >= 0; i-- {
 		podName := TiflowMasterPodName(tcName, i)
 		pod := &v1.Pod{}
@@ -94,39 +103,81 @@ func (u *masterUpgrader) gracefulUpgrade(tc *v1alpha1.TiflowCluster, oldSet *app
 
 		if revision == tc.Status.Master.StatefulSet.UpdateRevision {
 			if !podutil.IsPodReady(pod) {
+				if rolledBack, err := rollback.timeout(newSet, pod, "is not ready"); rolledBack || err != nil {
+					return err
+				}
+				progress.wait("pod %s to be ready", podName)
 				return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s upgraded tiflow pod: [%s] is not ready", ns, tcName, podName)
 			}
-			// TODO: add this after members update is supported
-			// if member, exist := tc.Status.Master.Members[podName]; !exist || !member.Health {
-			//	return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s tiflow-master upgraded pod: [%s] is not ready", ns, tcName, podName)
-			// }
+			member, exist := tc.Status.Master.Members[podName+"."+ns]
+			if !exist {
+				if rolledBack, err := rollback.timeout(newSet, pod, "does not join tiflow-master cluster"); rolledBack || err != nil {
+					return err
+				}
+				progress.wait("pod %s to join tiflow-master cluster", podName)
+				return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s upgraded tiflow-master pod: [%s] is not a member", ns, tcName, podName)
+			}
+			if !member.Health {
+				if rolledBack, err := rollback.timeout(newSet, pod, "is not healthy"); rolledBack || err != nil {
+					return err
+				}
+				progress.wait("pod %s to be healthy", podName)
+				return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s upgraded tiflow-master pod: [%s] is not healthy", ns, tcName, podName)
+			}
+			progress.podUpgraded(i, pod)
 			continue
 		}
 
-		return u.upgradeMasterPod(tc, i, newSet)
+		if hold, err := progress.hold(i); hold {
+			return err
+		}
+		return u.upgradeMasterPod(tc, i, newSet, progress)
 	}
 
 	return nil
 }
 
-func (u *masterUpgrader) upgradeMasterPod(tc *v1alpha1.TiflowCluster, ordinal int32, newSet *apps.StatefulSet) error {
+func (u *masterUpgrader) upgradeMasterPod(tc *v1alpha1.TiflowCluster, ordinal int32, newSet *apps.StatefulSet, progress *upgradeProgress) error {
 	ns := tc.GetNamespace()
 	tcName := tc.GetName()
 	upgradePodName := TiflowMasterPodName(tcName, ordinal)
+	// the pod is taken down only when the budget allows, unless its upgrade is already started
+	if *newSet.Spec.UpdateStrategy.RollingUpdate.Partition > ordinal {
+		if err := checkPodDisruptionBudget(context.TODO(), u.cli, tc, controller.TiflowMasterMemberName(tcName), 0); err != nil {
+			progress.wait("PodDisruptionBudget to allow upgrading pod %s", upgradePodName)
+			return err
+		}
+	}
+	// the pod is taken down only when the other members are healthy, unless its upgrade is already started
+	if *newSet.Spec.UpdateStrategy.RollingUpdate.Partition > ordinal {
+		if unhealthy := unhealthyMasterMembers(tc, *newSet.Spec.Replicas, upgradePodName); len(unhealthy) > 0 {
+			progress.wait("tiflow-master members %v to be healthy", unhealthy)
+			return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s tiflow-master members %v are not healthy, can't upgrade pod %s",
+				ns, tcName, unhealthy, upgradePodName)
+		}
+	}
+	progress.wait("pod %s to be upgraded", upgradePodName)
 	if strings.Contains(tc.Status.Master.Leader.ClientURL, TiflowMasterPeerSvcName(tcName, ordinal)) && tc.MasterStsActualReplicas() > 1 {
 		err := u.evictMasterLeader(tc, upgradePodName)
 		if err != nil {
 			klog.Errorf("tiflow-master upgrader: failed to evict tiflow-master %s's leader: %v", upgradePodName, err)
+			u.recorder.Eventf(tc, v1.EventTypeWarning, event.FailedEvictLeader, "failed to evict leader of tiflow-master %s before upgrading, error: %v",
+				upgradePodName, err)
 			return err
 		}
 		klog.Infof("tiflow-master upgrader: evict tiflow-master %s's leader successfully", upgradePodName)
+		u.recorder.Eventf(tc, v1.EventTypeNormal, event.EvictLeader, "evict leader of tiflow-master %s before upgrading", upgradePodName)
 		return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s tiflow-master member: evicting [%s]'s leader", ns, tcName, upgradePodName)
 	}
 
+	if *newSet.Spec.UpdateStrategy.RollingUpdate.Partition != ordinal {
+		u.recorder.Eventf(tc, v1.EventTypeNormal, event.UpgradePartition, "move upgrade partition of tiflow-master statefulSet %s to %d to upgrade pod %s",
+			newSet.GetName(), ordinal, upgradePodName)
+	}
 	mngerutils.SetUpgradePartition(newSet, ordinal)
 	return nil
 }
 
 func (u *masterUpgrader) evictMasterLeader(tc *v1alpha1.TiflowCluster, podName string) error {
-	return tiflowapi.GetMasterClient(u.cli, tc.GetNamespace(), tc.GetName(), podName, tc.IsClusterTLSEnabled()).EvictLeader()
+	return getMasterClient(u.cli, tc.GetNamespace(), tc.GetName(), podName, tc.IsClusterTLSEnabled()).EvictLeader()
 }
@@ -0,0 +1,190 @@
+package member
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/manager"
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
+// serviceMonitorGVK is the kind of ServiceMonitor defined by Prometheus Operator,
+// it's handled as unstructured object, so that the operator doesn't depend on Prometheus Operator.
+var serviceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+type serviceMonitorManager struct {
+	cli client.Client
+}
+
+// NewServiceMonitorManager returns a manager of the ServiceMonitors of tiflow-master and tiflow-executor
+func NewServiceMonitorManager(cli client.Client) manager.TiflowManager {
+	return &serviceMonitorManager{
+		cli: cli,
+	}
+}
+
+func (m *serviceMonitorManager) Sync(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster) error {
+	masterName := controller.TiflowMasterMemberName(tc.GetName())
+	if tc.MonitoringEnabled() && tc.Spec.Master != nil {
+		if err := m.syncServiceMonitor(ctx, tc, getMasterServiceMonitor(tc)); err != nil {
+			return err
+		}
+	} else if err := m.deleteServiceMonitor(ctx, tc, masterName); err != nil {
+		return err
+	}
+
+	executorName := controller.TiflowExecutorMemberName(tc.GetName())
+	if tc.MonitoringEnabled() && tc.Spec.Executor != nil {
+		return m.syncServiceMonitor(ctx, tc, getExecutorServiceMonitor(tc))
+	}
+	return m.deleteServiceMonitor(ctx, tc, executorName)
+}
+
+// Delete leaves the ServiceMonitors to the garbage collector.
+func (m *serviceMonitorManager) Delete(_ context.Context, _ *pingcapcomv1alpha1.TiflowCluster) error {
+	return nil
+}
+
+func (m *serviceMonitorManager) syncServiceMonitor(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster, desired *unstructured.Unstructured) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(serviceMonitorGVK)
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: ns, Name: desired.GetName()}, existing)
+	if meta.IsNoMatchError(err) {
+		klog.Warningf("tiflow cluster: [%s/%s] monitoring is enabled, but ServiceMonitor CRD is not installed, skip it", ns, tcName)
+		return nil
+	}
+	if errors.IsNotFound(err) {
+		klog.Infof("tiflow cluster: [%s/%s] creating ServiceMonitor %s", ns, tcName, desired.GetName())
+		return m.cli.Create(ctx, desired)
+	}
+	if err != nil {
+		return fmt.Errorf("syncServiceMonitor: failed to get ServiceMonitor %s/%s, error: %v", ns, desired.GetName(), err)
+	}
+
+	if apiequality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) &&
+		apiequality.Semantic.DeepEqual(existing.GetLabels(), desired.GetLabels()) {
+		return nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Object["spec"] = desired.Object["spec"]
+	updated.SetLabels(desired.GetLabels())
+	return m.cli.Update(ctx, updated)
+}
+
+// deleteServiceMonitor deletes the ServiceMonitor created for tc, it's looked up first so that nothing is
+// requested to delete on every reconciling of the clusters which have never enabled monitoring.
+func (m *serviceMonitorManager) deleteServiceMonitor(ctx context.Context, tc *pingcapcomv1alpha1.TiflowCluster, name string) error {
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: tc.GetNamespace(), Name: name}, sm)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleteServiceMonitor: failed to get ServiceMonitor %s/%s, error: %v", tc.GetNamespace(), name, err)
+	}
+	// leave the ServiceMonitors created by users alone
+	if !metav1.IsControlledBy(sm, tc) {
+		return nil
+	}
+
+	klog.Infof("tiflow cluster: [%s/%s] monitoring is disabled, deleting ServiceMonitor %s", tc.GetNamespace(), tc.GetName(), name)
+	err = m.cli.Delete(ctx, sm)
+	if err == nil || errors.IsNotFound(err) {
+		return nil
+	}
+	return fmt.Errorf("deleteServiceMonitor: failed to delete ServiceMonitor %s/%s, error: %v", tc.GetNamespace(), name, err)
+}
+
+func getMasterServiceMonitor(tc *pingcapcomv1alpha1.TiflowCluster) *unstructured.Unstructured {
+	portName := "tiflow-master"
+	if tc.Spec.Master.Service != nil && tc.Spec.Master.Service.PortName != nil {
+		portName = *tc.Spec.Master.Service.PortName
+	}
+	svcHost := fmt.Sprintf("%s.%s.svc", controller.TiflowMasterMemberName(tc.Name), tc.Namespace)
+
+	return newServiceMonitor(tc, controller.TiflowMasterMemberName(tc.Name),
+		label.New().Instance(tc.GetInstanceName()).TiflowMaster(), portName, svcHost)
+}
+
+func getExecutorServiceMonitor(tc *pingcapcomv1alpha1.TiflowCluster) *unstructured.Unstructured {
+	svcHost := fmt.Sprintf("%s.%s.svc", controller.TiflowExecutorPeerMemberName(tc.Name), tc.Namespace)
+
+	return newServiceMonitor(tc, controller.TiflowExecutorMemberName(tc.Name),
+		label.New().Instance(tc.GetInstanceName()).TiflowExecutor(), "tiflow-executor", svcHost)
+}
+
+// newServiceMonitor builds a ServiceMonitor which scrapes the port of services selected by selector.
+// The port name is used to tell the services of the same component apart.
+func newServiceMonitor(tc *pingcapcomv1alpha1.TiflowCluster, name string, selector label.Label, portName, serverName string) *unstructured.Unstructured {
+	endpoint := map[string]interface{}{
+		"port": portName,
+		"path": "/metrics",
+	}
+	if tc.Spec.Monitoring.Interval != "" {
+		endpoint["interval"] = tc.Spec.Monitoring.Interval
+	}
+	if tc.IsClusterTLSEnabled() {
+		secretName := util.ClusterClientTLSSecretName(tc.Name)
+		endpoint["scheme"] = "https"
+		endpoint["tlsConfig"] = map[string]interface{}{
+			"ca":         secretKeySelector(secretName, corev1.ServiceAccountRootCAKey),
+			"cert":       secretKeySelector(secretName, corev1.TLSCertKey),
+			"keySecret":  secretKeySelector(secretName, corev1.TLSPrivateKeyKey)["secret"],
+			"serverName": serverName,
+		}
+	}
+
+	matchLabels := map[string]interface{}{}
+	for k, v := range selector.Labels() {
+		matchLabels[k] = v
+	}
+
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	sm.SetName(name)
+	sm.SetNamespace(tc.Namespace)
+	sm.SetLabels(util.CombineStringMap(selector.Copy().Labels(), tc.Spec.Monitoring.Labels))
+	sm.SetOwnerReferences([]metav1.OwnerReference{controller.GetOwnerRef(tc)})
+	sm.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": matchLabels,
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchNames": []interface{}{tc.Namespace},
+		},
+		"endpoints": []interface{}{endpoint},
+	}
+	return sm
+}
+
+func secretKeySelector(secretName, key string) map[string]interface{} {
+	return map[string]interface{}{
+		"secret": map[string]interface{}{
+			"name": secretName,
+			"key":  key,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package member
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+// deleteCountingClient counts the delete requests, and pretends the ServiceMonitor CRD is absent if noCRD is set.
+type deleteCountingClient struct {
+	client.Client
+	noCRD   bool
+	deletes int
+}
+
+func (c *deleteCountingClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	if c.noCRD {
+		return &meta.NoKindMatchError{GroupKind: serviceMonitorGVK.GroupKind(), SearchedVersions: []string{serviceMonitorGVK.Version}}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *deleteCountingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.deletes++
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func getServiceMonitor(cli client.Client, name string) *unstructured.Unstructured {
+	sm := &unstructured.Unstructured{}
+	sm.SetGroupVersionKind(serviceMonitorGVK)
+	err := cli.Get(context.TODO(), types.NamespacedName{Namespace: "ns-1", Name: name}, sm)
+	if err != nil {
+		return nil
+	}
+	return sm
+}
+
+func TestServiceMonitorSync(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo", UID: "demo-uid"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master:     &v1alpha1.MasterSpec{Replicas: 3},
+			Executor:   &v1alpha1.ExecutorSpec{Replicas: 3},
+			Monitoring: &v1alpha1.MonitoringSpec{Enabled: true, Interval: "30s"},
+		},
+	}
+	cli := &deleteCountingClient{Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()}
+	m := &serviceMonitorManager{cli: cli}
+
+	// create
+	require.NoError(t, m.Sync(context.TODO(), tc))
+	for _, name := range []string{"demo-tiflow-master", "demo-tiflow-executor"} {
+		sm := getServiceMonitor(cli, name)
+		require.NotNil(t, sm, name)
+		require.True(t, metav1.IsControlledBy(sm, tc))
+		endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+		require.Equal(t, "30s", endpoints[0].(map[string]interface{})["interval"])
+	}
+
+	// update
+	tc.Spec.Monitoring.Interval = "15s"
+	tc.Spec.Monitoring.Labels = map[string]string{"release": "prometheus"}
+	require.NoError(t, m.Sync(context.TODO(), tc))
+	sm := getServiceMonitor(cli, "demo-tiflow-master")
+	endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	require.Equal(t, "15s", endpoints[0].(map[string]interface{})["interval"])
+	require.Equal(t, "prometheus", sm.GetLabels()["release"])
+
+	// disable, the ServiceMonitor of the same name created by users is left alone
+	userSM := getServiceMonitor(cli, "demo-tiflow-executor")
+	userSM.SetOwnerReferences(nil)
+	require.NoError(t, cli.Update(context.TODO(), userSM))
+	tc.Spec.Monitoring.Enabled = false
+	require.NoError(t, m.Sync(context.TODO(), tc))
+	require.Nil(t, getServiceMonitor(cli, "demo-tiflow-master"))
+	require.NotNil(t, getServiceMonitor(cli, "demo-tiflow-executor"))
+	require.Equal(t, 1, cli.deletes)
+
+	// nothing is requested to delete once the ServiceMonitors are gone
+	require.NoError(t, m.Sync(context.TODO(), tc))
+	require.Equal(t, 1, cli.deletes)
+
+	// nor is it when the CRD is not installed
+	cli.noCRD = true
+	require.NoError(t, m.Sync(context.TODO(), tc))
+	require.Equal(t, 1, cli.deletes)
+	tc.Spec.Monitoring.Enabled = true
+	require.NoError(t, m.Sync(context.TODO(), tc))
+}
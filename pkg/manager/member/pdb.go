@@ -0,0 +1,160 @@
+package member
+
+import (
+	"context"
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+)
+
+// masterMaxUnavailable returns how many tiflow-masters can be unavailable while a majority of them is still up
+func masterMaxUnavailable(replicas int32) int32 {
+	return (replicas - 1) / 2
+}
+
+// syncMasterPodDisruptionBudget keeps a majority of tiflow-masters up during node drains.
+// The budget follows the replicas of the statefulSet, so it's adjusted step by step during scaling.
+// A single tiflow-master has no quorum to protect, its PDB is removed, so is a suspended one.
+func syncMasterPodDisruptionBudget(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster) error {
+	pdbName := controller.TiflowMasterMemberName(tc.GetName())
+	replicas, err := currentReplicas(ctx, cli, tc, pdbName, tc.Spec.Master.Replicas)
+	if err != nil {
+		return err
+	}
+	if replicas <= 1 || tc.IsPaused() {
+		return deletePodDisruptionBudget(ctx, cli, tc, pdbName)
+	}
+
+	maxUnavailable := intstr.FromInt(int(masterMaxUnavailable(replicas)))
+	return syncPodDisruptionBudget(ctx, cli, newPodDisruptionBudget(tc, pdbName,
+		label.New().Instance(tc.GetInstanceName()).TiflowMaster(), maxUnavailable))
+}
+
+// syncExecutorPodDisruptionBudget limits the tiflow-executors disrupted at the same time by spec.executor.podDisruptionBudget
+func syncExecutorPodDisruptionBudget(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster) error {
+	pdbName := controller.TiflowExecutorMemberName(tc.GetName())
+	replicas, err := currentReplicas(ctx, cli, tc, pdbName, tc.Spec.Executor.Replicas)
+	if err != nil {
+		return err
+	}
+	if replicas == 0 || tc.IsPaused() {
+		return deletePodDisruptionBudget(ctx, cli, tc, pdbName)
+	}
+
+	maxUnavailable := intstr.FromInt(1)
+	if spec := tc.Spec.Executor.PodDisruptionBudget; spec != nil && spec.MaxUnavailable != nil {
+		maxUnavailable = *spec.MaxUnavailable
+	}
+	return syncPodDisruptionBudget(ctx, cli, newPodDisruptionBudget(tc, pdbName,
+		label.New().Instance(tc.GetInstanceName()).TiflowExecutor(), maxUnavailable))
+}
+
+// currentReplicas returns the replicas of the statefulSet, or the desired replicas if it's not created yet
+func currentReplicas(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, stsName string, desired int32) (int32, error) {
+	sts := &apps.StatefulSet{}
+	err := cli.Get(ctx, types.NamespacedName{Namespace: tc.GetNamespace(), Name: stsName}, sts)
+	if errors.IsNotFound(err) {
+		return desired, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("currentReplicas: failed to get sts %s/%s, error: %v", tc.GetNamespace(), stsName, err)
+	}
+	if sts.Spec.Replicas == nil {
+		return desired, nil
+	}
+	return *sts.Spec.Replicas, nil
+}
+
+func newPodDisruptionBudget(tc *v1alpha1.TiflowCluster, name string, selector label.Label, maxUnavailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tc.GetNamespace(),
+			Labels:          selector.Copy().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetOwnerRef(tc)},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       selector.LabelSelector(),
+		},
+	}
+}
+
+func syncPodDisruptionBudget(ctx context.Context, cli client.Client, newPDB *policyv1.PodDisruptionBudget) error {
+	oldPDB := &policyv1.PodDisruptionBudget{}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(newPDB), oldPDB)
+	if errors.IsNotFound(err) {
+		klog.Infof("creating PodDisruptionBudget %s/%s with maxUnavailable %s", newPDB.Namespace, newPDB.Name, newPDB.Spec.MaxUnavailable.String())
+		return cli.Create(ctx, newPDB)
+	}
+	if err != nil {
+		return fmt.Errorf("syncPodDisruptionBudget: failed to get pdb %s/%s, error: %v", newPDB.Namespace, newPDB.Name, err)
+	}
+
+	if apiequality.Semantic.DeepEqual(oldPDB.Spec.MaxUnavailable, newPDB.Spec.MaxUnavailable) &&
+		apiequality.Semantic.DeepEqual(oldPDB.Spec.Selector, newPDB.Spec.Selector) &&
+		apiequality.Semantic.DeepEqual(oldPDB.Labels, newPDB.Labels) {
+		return nil
+	}
+
+	pdb := oldPDB.DeepCopy()
+	pdb.Labels = newPDB.Labels
+	pdb.Spec.MinAvailable = nil
+	pdb.Spec.MaxUnavailable = newPDB.Spec.MaxUnavailable
+	pdb.Spec.Selector = newPDB.Spec.Selector
+	klog.Infof("updating PodDisruptionBudget %s/%s with maxUnavailable %s", newPDB.Namespace, newPDB.Name, newPDB.Spec.MaxUnavailable.String())
+	return cli.Update(ctx, pdb)
+}
+
+func deletePodDisruptionBudget(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, name string) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	err := cli.Get(ctx, types.NamespacedName{Namespace: tc.GetNamespace(), Name: name}, pdb)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deletePodDisruptionBudget: failed to get pdb %s/%s, error: %v", tc.GetNamespace(), name, err)
+	}
+	if !metav1.IsControlledBy(pdb, tc) {
+		return nil
+	}
+	return client.IgnoreNotFound(cli.Delete(ctx, pdb))
+}
+
+// checkPodDisruptionBudget returns a RequeueError if taking one more pod down breaks the PodDisruptionBudget,
+// pending is the number of pods taken down in this round, which are not observed by the budget yet.
+// A budget allowing no disruption at all is only respected while some other pod is unhealthy,
+// otherwise a component with too few replicas could never be upgraded.
+func checkPodDisruptionBudget(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, name string, pending int32) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	err := cli.Get(ctx, types.NamespacedName{Namespace: tc.GetNamespace(), Name: name}, pdb)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checkPodDisruptionBudget: failed to get pdb %s/%s, error: %v", tc.GetNamespace(), name, err)
+	}
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s PodDisruptionBudget %s is not observed yet", tc.GetNamespace(), tc.GetName(), name)
+	}
+	if pdb.Status.DisruptionsAllowed > pending {
+		return nil
+	}
+	if pending == 0 && pdb.Status.CurrentHealthy >= pdb.Status.ExpectedPods && len(pdb.Status.DisruptedPods) == 0 {
+		return nil
+	}
+	return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s PodDisruptionBudget %s allows no more disruption, healthy pods: %d/%d",
+		tc.GetNamespace(), tc.GetName(), name, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods)
+}
@@ -0,0 +1,109 @@
+package member
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+)
+
+func TestMasterMaxUnavailable(t *testing.T) {
+	for replicas, expected := range map[int32]int32{2: 0, 3: 1, 4: 1, 5: 2} {
+		require.Equal(t, expected, masterMaxUnavailable(replicas), "replicas: %d", replicas)
+	}
+}
+
+func TestSyncPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master: &v1alpha1.MasterSpec{Replicas: 5},
+			Executor: &v1alpha1.ExecutorSpec{
+				Replicas: 4,
+				PodDisruptionBudget: &v1alpha1.PodDisruptionBudgetSpec{
+					MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "50%"},
+				},
+			},
+		},
+	}
+	// tiflow-master is scaling out from 3 to 5
+	masterSts := &apps.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: controller.TiflowMasterMemberName("demo")},
+		Spec:       apps.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(masterSts).Build()
+
+	require.NoError(t, syncMasterPodDisruptionBudget(ctx, cli, tc))
+	require.NoError(t, syncExecutorPodDisruptionBudget(ctx, cli, tc))
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-master"}, pdb))
+	require.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+	require.Equal(t, "tiflow-master", pdb.Spec.Selector.MatchLabels["app.kubernetes.io/component"])
+
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor"}, pdb))
+	require.Equal(t, "50%", pdb.Spec.MaxUnavailable.String())
+
+	// the budget follows the statefulSet
+	masterSts.Spec.Replicas = pointer.Int32Ptr(5)
+	require.NoError(t, cli.Update(ctx, masterSts))
+	require.NoError(t, syncMasterPodDisruptionBudget(ctx, cli, tc))
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-master"}, pdb))
+	require.Equal(t, 2, pdb.Spec.MaxUnavailable.IntValue())
+
+	// a single tiflow-master has no PDB
+	masterSts.Spec.Replicas = pointer.Int32Ptr(1)
+	require.NoError(t, cli.Update(ctx, masterSts))
+	require.NoError(t, syncMasterPodDisruptionBudget(ctx, cli, tc))
+	err := cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-master"}, pdb)
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestCheckPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"}}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo-tiflow-master"},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			DisruptionsAllowed: 0,
+			CurrentHealthy:     2,
+			ExpectedPods:       2,
+		},
+	}
+
+	// no budget
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	require.NoError(t, checkPodDisruptionBudget(ctx, cli, tc, pdb.Name, 0))
+
+	// all pods are healthy
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pdb.DeepCopy()).Build()
+	require.NoError(t, checkPodDisruptionBudget(ctx, cli, tc, pdb.Name, 0))
+
+	// another pod is down
+	pdb.Status.CurrentHealthy = 1
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pdb.DeepCopy()).Build()
+	err := checkPodDisruptionBudget(ctx, cli, tc, pdb.Name, 0)
+	require.True(t, controller.IsRequeueError(err))
+
+	// the budget still allows a disruption
+	pdb.Status.DisruptionsAllowed = 1
+	cli = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pdb.DeepCopy()).Build()
+	require.NoError(t, checkPodDisruptionBudget(ctx, cli, tc, pdb.Name, 0))
+
+	// the disruption is taken by another pod in this round
+	err = checkPodDisruptionBudget(ctx, cli, tc, pdb.Name, 1)
+	require.True(t, controller.IsRequeueError(err))
+}
@@ -0,0 +1,194 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
+)
+
+// podOperator processes the operations requested by the annotations of single pods of a component,
+// every processed operation is acknowledged by an event recorded on the TiflowCluster.
+type podOperator struct {
+	cli      client.Client
+	recorder record.EventRecorder
+	tc       *v1alpha1.TiflowCluster
+	member   v1alpha1.MemberType
+}
+
+func newPodOperator(cli client.Client, recorder record.EventRecorder, tc *v1alpha1.TiflowCluster, member v1alpha1.MemberType) *podOperator {
+	return &podOperator{
+		cli:      cli,
+		recorder: recorder,
+		tc:       tc,
+		member:   member,
+	}
+}
+
+// syncPodOperations processes the debug mode, deletion and leader eviction requested on the pods of the component
+func syncPodOperations(ctx context.Context, cli client.Client, recorder record.EventRecorder, tc *v1alpha1.TiflowCluster, member v1alpha1.MemberType) error {
+	return newPodOperator(cli, recorder, tc, member).sync(ctx)
+}
+
+func (o *podOperator) sync(ctx context.Context) error {
+	pods, err := listMemberPods(ctx, o.cli, o.tc, o.member)
+	if err != nil {
+		return err
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if err := o.evictLeader(ctx, pod); err != nil {
+			return err
+		}
+		if err := o.debugMode(ctx, pod); err != nil {
+			return err
+		}
+		if err := o.deletePod(ctx, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evictLeader evicts the leader from the tiflow-master pod once, the request annotation is removed as the acknowledgement
+func (o *podOperator) evictLeader(ctx context.Context, pod *corev1.Pod) error {
+	if pod.Annotations[label.AnnEvictLeaderKey] != label.AnnEvictLeaderVal {
+		return nil
+	}
+
+	switch {
+	case o.member != v1alpha1.TiFlowMasterMemberType:
+		o.recorder.Eventf(o.tc, corev1.EventTypeWarning, event.FailedEvictLeader, "%s %s has no leader to evict", o.member, pod.Name)
+	case !isMasterLeader(o.tc, pod.Name):
+		o.recorder.Eventf(o.tc, corev1.EventTypeNormal, event.EvictLeader, "tiflow-master %s is not the leader, nothing to evict", pod.Name)
+	default:
+		if err := o.evictMasterLeader(pod.Name, "by annotation"); err != nil {
+			return err
+		}
+	}
+
+	delete(pod.Annotations, label.AnnEvictLeaderKey)
+	return o.cli.Update(ctx, pod)
+}
+
+// debugMode restarts the container of the pod in or out of debug mode by the runmode annotation read by the start script
+func (o *podOperator) debugMode(ctx context.Context, pod *corev1.Pod) error {
+	wanted := pod.Annotations[label.AnnDebugModeKey] == label.AnnDebugModeVal
+	running := pod.Annotations[label.AnnRunModeKey] == label.AnnRunModeDebug
+	if wanted == running {
+		return nil
+	}
+
+	if wanted {
+		if err := o.evictLeaderBefore(pod.Name, "entering debug mode"); err != nil {
+			return err
+		}
+		pod.Annotations[label.AnnRunModeKey] = label.AnnRunModeDebug
+	} else {
+		delete(pod.Annotations, label.AnnRunModeKey)
+	}
+	if err := o.cli.Update(ctx, pod); err != nil {
+		return fmt.Errorf("syncPodOperations: failed to update runmode of pod %s/%s, error: %v", pod.Namespace, pod.Name, err)
+	}
+
+	if wanted {
+		klog.Infof("tiflow cluster [%s/%s]'s %s pod %s is entering debug mode", o.tc.GetNamespace(), o.tc.GetName(), o.member, pod.Name)
+		o.recorder.Eventf(o.tc, corev1.EventTypeNormal, event.DebugMode, "%s %s enters debug mode, its container is restarted without starting %s",
+			o.member, pod.Name, o.member)
+	} else {
+		klog.Infof("tiflow cluster [%s/%s]'s %s pod %s is leaving debug mode", o.tc.GetNamespace(), o.tc.GetName(), o.member, pod.Name)
+		o.recorder.Eventf(o.tc, corev1.EventTypeNormal, event.DebugMode, "%s %s leaves debug mode, %s is started again",
+			o.member, pod.Name, o.member)
+	}
+	return nil
+}
+
+// deletePod deletes the pod gracefully, the statefulSet recreates it. The leader is evicted from a tiflow-master first,
+// and the pod is only deleted when the PodDisruptionBudget allows.
+func (o *podOperator) deletePod(ctx context.Context, pod *corev1.Pod) error {
+	if pod.Annotations[label.AnnDeletePodKey] != label.AnnDeletePodVal {
+		return nil
+	}
+
+	if err := o.evictLeaderBefore(pod.Name, "deleting the pod"); err != nil {
+		return err
+	}
+	pdbName := controller.TiflowExecutorMemberName(o.tc.GetName())
+	if o.member == v1alpha1.TiFlowMasterMemberType {
+		pdbName = controller.TiflowMasterMemberName(o.tc.GetName())
+	}
+	if err := checkPodDisruptionBudget(ctx, o.cli, o.tc, pdbName, 0); err != nil {
+		return err
+	}
+
+	if err := client.IgnoreNotFound(o.cli.Delete(ctx, pod)); err != nil {
+		return fmt.Errorf("syncPodOperations: failed to delete pod %s/%s, error: %v", pod.Namespace, pod.Name, err)
+	}
+	klog.Infof("tiflow cluster [%s/%s]'s %s pod %s is deleted by annotation", o.tc.GetNamespace(), o.tc.GetName(), o.member, pod.Name)
+	o.recorder.Eventf(o.tc, corev1.EventTypeNormal, event.DeletePod, "%s %s is deleted by annotation, it will be recreated", o.member, pod.Name)
+	return nil
+}
+
+// evictLeaderBefore evicts the leader from the tiflow-master pod and requeues until another member takes over,
+// it does nothing for tiflow-executors, members which are not the leader or the only tiflow-master.
+func (o *podOperator) evictLeaderBefore(podName, action string) error {
+	if o.member != v1alpha1.TiFlowMasterMemberType || !isMasterLeader(o.tc, podName) || o.tc.MasterStsActualReplicas() <= 1 {
+		return nil
+	}
+	if err := o.evictMasterLeader(podName, "before "+action); err != nil {
+		return err
+	}
+	return controller.RequeueErrorf("tiflowcluster: [%s/%s]'s tiflow-master member: evicting [%s]'s leader before %s",
+		o.tc.GetNamespace(), o.tc.GetName(), podName, action)
+}
+
+func (o *podOperator) evictMasterLeader(podName, cause string) error {
+	err := getMasterClient(o.cli, o.tc.GetNamespace(), o.tc.GetName(), podName, o.tc.IsClusterTLSEnabled()).EvictLeader()
+	if err != nil {
+		klog.Errorf("tiflow-master pod operator: failed to evict tiflow-master %s's leader: %v", podName, err)
+		o.recorder.Eventf(o.tc, corev1.EventTypeWarning, event.FailedEvictLeader, "failed to evict leader of tiflow-master %s %s, error: %v",
+			podName, cause, err)
+		return err
+	}
+	o.recorder.Eventf(o.tc, corev1.EventTypeNormal, event.EvictLeader, "evict leader of tiflow-master %s %s", podName, cause)
+	return nil
+}
+
+// isMasterLeader returns whether the tiflow-master pod is the leader last observed in status
+func isMasterLeader(tc *v1alpha1.TiflowCluster, podName string) bool {
+	addr := tc.Status.Master.Leader.ClientURL
+	if i := strings.Index(addr, "://"); i >= 0 {
+		addr = addr[i+3:]
+	}
+	return strings.HasPrefix(addr, podName+".")
+}
+
+// listMemberPods returns the pods of the component of tc
+func listMemberPods(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, member v1alpha1.MemberType) ([]corev1.Pod, error) {
+	selector := label.New().Instance(tc.GetInstanceName())
+	if member == v1alpha1.TiFlowMasterMemberType {
+		selector = selector.TiflowMaster()
+	} else {
+		selector = selector.TiflowExecutor()
+	}
+
+	pods := &corev1.PodList{}
+	if err := cli.List(ctx, pods, client.InNamespace(tc.GetNamespace()), client.MatchingLabels(selector.Labels())); err != nil {
+		return nil, fmt.Errorf("listMemberPods: failed to list pods of %s for cluster %s/%s, error: %v",
+			member, tc.GetNamespace(), tc.GetName(), err)
+	}
+	return pods.Items, nil
+}
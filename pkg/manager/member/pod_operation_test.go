@@ -0,0 +1,67 @@
+package member
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestSyncPodOperations(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"}}
+	tc.Status.Master.Leader.ClientURL = "demo-tiflow-master-0.demo-tiflow-master-peer.ns-1.svc:10240"
+	newPod := func(name string, labels label.Label, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns-1",
+			Name:        name,
+			Labels:      labels.Labels(),
+			Annotations: annotations,
+		}}
+	}
+	executorLabels := label.New().Instance("demo").TiflowExecutor()
+	masterLabels := label.New().Instance("demo").TiflowMaster()
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newPod("demo-tiflow-executor-0", executorLabels, map[string]string{label.AnnDebugModeKey: label.AnnDebugModeVal}),
+		newPod("demo-tiflow-executor-1", executorLabels, map[string]string{label.AnnDeletePodKey: label.AnnDeletePodVal}),
+		newPod("demo-tiflow-master-1", masterLabels, map[string]string{label.AnnEvictLeaderKey: label.AnnEvictLeaderVal}),
+	).Build()
+	recorder := record.NewFakeRecorder(10)
+
+	require.NoError(t, syncPodOperations(ctx, cli, recorder, tc, v1alpha1.TiFlowExecutorMemberType))
+	require.NoError(t, syncPodOperations(ctx, cli, recorder, tc, v1alpha1.TiFlowMasterMemberType))
+
+	// executor-0 is restarted in debug mode by the start script
+	pod := &corev1.Pod{}
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor-0"}, pod))
+	require.Equal(t, label.AnnRunModeDebug, pod.Annotations[label.AnnRunModeKey])
+	// executor-1 is deleted to be recreated by the statefulSet
+	err := cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor-1"}, pod)
+	require.True(t, errors.IsNotFound(err))
+	// master-1 is not the leader, the request is acknowledged without evicting
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-master-1"}, pod))
+	require.NotContains(t, pod.Annotations, label.AnnEvictLeaderKey)
+	require.Len(t, recorder.Events, 3)
+
+	// executor-0 leaves debug mode once the request is removed
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor-0"}, pod))
+	delete(pod.Annotations, label.AnnDebugModeKey)
+	require.NoError(t, cli.Update(ctx, pod))
+	require.NoError(t, syncPodOperations(ctx, cli, recorder, tc, v1alpha1.TiFlowExecutorMemberType))
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: "ns-1", Name: "demo-tiflow-executor-0"}, pod))
+	require.NotContains(t, pod.Annotations, label.AnnRunModeKey)
+	require.Len(t, recorder.Events, 4)
+
+	require.True(t, isMasterLeader(tc, "demo-tiflow-master-0"))
+	require.False(t, isMasterLeader(tc, "demo-tiflow-master-1"))
+}
@@ -0,0 +1,181 @@
+package member
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
+	"github.com/pingcap/tiflow-operator/pkg/manager"
+)
+
+const (
+	// leaderTransferBaseBackoff is how long to wait after the first resignation of a leader out of the preferred members
+	leaderTransferBaseBackoff = 30 * time.Second
+	// leaderTransferMaxBackoff limits the wait between resignations
+	leaderTransferMaxBackoff = 10 * time.Minute
+)
+
+// leaderTransferBackoff returns how long to wait after the given number of resignations before asking the leader again
+func leaderTransferBackoff(attempts int32) time.Duration {
+	backoff := leaderTransferBaseBackoff
+	for i := int32(1); i < attempts; i++ {
+		backoff *= 2
+		if backoff >= leaderTransferMaxBackoff {
+			return leaderTransferMaxBackoff
+		}
+	}
+	return backoff
+}
+
+type preferredLeaderManager struct {
+	cli      client.Client
+	recorder record.EventRecorder
+}
+
+// NewPreferredLeaderManager returns a manager which moves the tiflow-master leader to the preferred members,
+// it's synced after the other members, so they are not held back while a transfer is pending.
+func NewPreferredLeaderManager(cli client.Client, recorder record.EventRecorder) manager.TiflowManager {
+	return &preferredLeaderManager{
+		cli:      cli,
+		recorder: recorder,
+	}
+}
+
+func (m *preferredLeaderManager) Sync(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	if tc.Spec.Master == nil {
+		return nil
+	}
+	return syncPreferredLeader(ctx, m.cli, m.recorder, tc)
+}
+
+// Delete has nothing to tear down.
+func (m *preferredLeaderManager) Delete(_ context.Context, _ *v1alpha1.TiflowCluster) error {
+	return nil
+}
+
+// syncPreferredLeader moves the tiflow-master leader to a preferred member by asking the leader to resign.
+// EvictLeader doesn't choose the next leader, so it's retried with backoff until a preferred member is elected,
+// tc is requeued while the transfer is pending.
+// Nothing is done while tiflow-master is upgrading or scaling, or no preferred member is ready and healthy to take over.
+func syncPreferredLeader(ctx context.Context, cli client.Client, recorder record.EventRecorder, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.Status.Master.Leader.ClientURL == "" || tc.MasterUpgrading() || tc.MasterScaling() {
+		return nil
+	}
+
+	pods, err := listMemberPods(ctx, cli, tc, v1alpha1.TiFlowMasterMemberType)
+	if err != nil {
+		return err
+	}
+	preferred, err := preferredLeaderPods(ctx, cli, tc, pods)
+	if err != nil {
+		return err
+	}
+	if len(preferred) == 0 {
+		tc.Status.Master.LeaderTransfer = nil
+		return nil
+	}
+
+	var leader *corev1.Pod
+	candidates := 0
+	for i := range pods {
+		pod := &pods[i]
+		if isMasterLeader(tc, pod.Name) {
+			leader = pod
+		}
+		member := tc.Status.Master.Members[pod.Name+"."+ns]
+		if preferred[pod.Name] && pod.DeletionTimestamp == nil && podutil.IsPodReady(pod) && member.Health {
+			candidates++
+		}
+	}
+	if leader == nil {
+		return nil
+	}
+	if preferred[leader.Name] {
+		if tc.Status.Master.LeaderTransfer != nil {
+			klog.Infof("tiflow cluster [%s/%s]'s tiflow-master leader is moved to the preferred member %s", ns, tcName, leader.Name)
+			tc.Status.Master.LeaderTransfer = nil
+		}
+		return nil
+	}
+	if candidates == 0 {
+		klog.Infof("tiflow cluster [%s/%s] has no preferred tiflow-master ready to take over the leader from %s", ns, tcName, leader.Name)
+		return nil
+	}
+
+	rec := tc.Status.Master.LeaderTransfer
+	if rec == nil {
+		rec = &v1alpha1.LeaderTransferRecord{}
+	}
+	if wait := leaderTransferBackoff(rec.Attempts) - time.Since(rec.LastAttemptTime.Time); rec.Attempts > 0 && wait > 0 {
+		return controller.RequeueErrorf("tiflow cluster [%s/%s] is moving tiflow-master leader from %s to a preferred member, next attempt in %s",
+			ns, tcName, leader.Name, wait.Round(time.Second))
+	}
+	rec.Attempts++
+	rec.LastAttemptTime = metav1.Now()
+	tc.Status.Master.LeaderTransfer = rec
+
+	err = getMasterClient(cli, ns, tcName, leader.Name, tc.IsClusterTLSEnabled()).EvictLeader()
+	if err != nil {
+		klog.Errorf("tiflow cluster [%s/%s] failed to evict tiflow-master %s's leader: %v", ns, tcName, leader.Name, err)
+		recorder.Eventf(tc, corev1.EventTypeWarning, event.FailedEvictLeader,
+			"failed to evict leader of tiflow-master %s to move it to a preferred member, attempt %d, error: %v", leader.Name, rec.Attempts, err)
+		return controller.RequeueErrorf("tiflow cluster [%s/%s] failed to evict tiflow-master %s's leader, attempt %d",
+			ns, tcName, leader.Name, rec.Attempts)
+	}
+	klog.Infof("tiflow cluster [%s/%s] evicts tiflow-master %s's leader to move it to a preferred member, attempt %d",
+		ns, tcName, leader.Name, rec.Attempts)
+	recorder.Eventf(tc, corev1.EventTypeNormal, event.EvictLeader,
+		"evict leader of tiflow-master %s to move it to a preferred member, attempt %d", leader.Name, rec.Attempts)
+	return controller.RequeueErrorf("tiflow cluster [%s/%s] evicts tiflow-master %s's leader, waiting for a preferred member to be elected",
+		ns, tcName, leader.Name)
+}
+
+// preferredLeaderPods returns the names of the tiflow-master pods preferred as the leader,
+// which are annotated as preferred or scheduled to the nodes in spec.master.preferredLeaderZones.
+func preferredLeaderPods(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, pods []corev1.Pod) (map[string]bool, error) {
+	zones := make(map[string]bool, len(tc.Spec.Master.PreferredLeaderZones))
+	for _, zone := range tc.Spec.Master.PreferredLeaderZones {
+		zones[zone] = true
+	}
+
+	preferred := make(map[string]bool)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Annotations[label.AnnPreferredLeaderKey] == label.AnnPreferredLeaderVal {
+			preferred[pod.Name] = true
+			continue
+		}
+		if len(zones) == 0 || pod.Spec.NodeName == "" {
+			continue
+		}
+		node := &corev1.Node{}
+		err := cli.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("preferredLeaderPods: failed to get node %s of pod %s/%s, error: %v",
+				pod.Spec.NodeName, pod.Namespace, pod.Name, err)
+		}
+		if zones[node.Labels[corev1.LabelTopologyZone]] {
+			preferred[pod.Name] = true
+		}
+	}
+	return preferred, nil
+}
@@ -0,0 +1,103 @@
+package member
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+func TestLeaderTransferBackoff(t *testing.T) {
+	require.Equal(t, 30*time.Second, leaderTransferBackoff(1))
+	require.Equal(t, time.Minute, leaderTransferBackoff(2))
+	require.Equal(t, 4*time.Minute, leaderTransferBackoff(4))
+	require.Equal(t, 10*time.Minute, leaderTransferBackoff(10))
+}
+
+func TestSyncPreferredLeader(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: v1alpha1.TiflowClusterSpec{
+			Master: &v1alpha1.MasterSpec{Replicas: 3, PreferredLeaderZones: []string{"zone-a"}},
+		},
+	}
+	masterLabels := label.New().Instance("demo").TiflowMaster().Labels()
+	newPod := func(name, node string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: name, Labels: masterLabels, Annotations: annotations},
+			Spec:       corev1.PodSpec{NodeName: node},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+	newNode := func(name, zone string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelTopologyZone: zone}}}
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+		newNode("node-a", "zone-a"), newNode("node-b", "zone-b"),
+		newPod("demo-tiflow-master-0", "node-a", nil),
+		newPod("demo-tiflow-master-1", "node-b", nil),
+		newPod("demo-tiflow-master-2", "node-b", map[string]string{label.AnnPreferredLeaderKey: label.AnnPreferredLeaderVal}),
+	).Build()
+
+	pods, err := listMemberPods(ctx, cli, tc, v1alpha1.TiFlowMasterMemberType)
+	require.NoError(t, err)
+	preferred, err := preferredLeaderPods(ctx, cli, tc, pods)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"demo-tiflow-master-0": true, "demo-tiflow-master-2": true}, preferred)
+
+	// the leader out of the preferred zones is not asked again before backoff
+	recorder := record.NewFakeRecorder(10)
+	tc.Status.Master.Members = map[string]v1alpha1.MasterMember{
+		"demo-tiflow-master-0.ns-1": {Health: true},
+		"demo-tiflow-master-1.ns-1": {Health: true},
+		"demo-tiflow-master-2.ns-1": {Health: true},
+	}
+	tc.Status.Master.Leader.ClientURL = "demo-tiflow-master-1.demo-tiflow-master-peer.ns-1.svc:10240"
+	tc.Status.Master.LeaderTransfer = &v1alpha1.LeaderTransferRecord{Attempts: 2, LastAttemptTime: metav1.Now()}
+	m := NewPreferredLeaderManager(cli, recorder)
+	// the pending transfer is requeued to be attempted again after backoff
+	err = m.Sync(ctx, tc)
+	require.True(t, controller.IsRequeueError(err), err)
+	require.Contains(t, err.Error(), "next attempt in 1m0s")
+	require.Equal(t, int32(2), tc.Status.Master.LeaderTransfer.Attempts)
+	require.Empty(t, recorder.Events)
+
+	// the leader is asked again once backoff passes, and it's requeued to check the election
+	resigned := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/leader/resign" {
+			resigned++
+		}
+	}))
+	defer ts.Close()
+	defer func(f func(client.Client, string, string, string, bool) tiflowapi.MasterClient) { getMasterClient = f }(getMasterClient)
+	getMasterClient = func(client.Client, string, string, string, bool) tiflowapi.MasterClient {
+		return tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil)
+	}
+	tc.Status.Master.LeaderTransfer.LastAttemptTime = metav1.NewTime(time.Now().Add(-time.Minute))
+	err = m.Sync(ctx, tc)
+	require.True(t, controller.IsRequeueError(err), err)
+	require.Equal(t, 1, resigned)
+	require.Equal(t, int32(3), tc.Status.Master.LeaderTransfer.Attempts)
+	require.Contains(t, <-recorder.Events, "attempt 3")
+
+	// the record is cleared once a preferred member takes over
+	tc.Status.Master.Leader.ClientURL = "demo-tiflow-master-0.demo-tiflow-master-peer.ns-1.svc:10240"
+	require.NoError(t, m.Sync(ctx, tc))
+	require.Nil(t, tc.Status.Master.LeaderTransfer)
+}
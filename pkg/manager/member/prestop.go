@@ -0,0 +1,114 @@
+package member
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/component"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/prestop"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+const (
+	prestopVolumeName = "prestop"
+	prestopBinDir     = "/var/lib/tiflow-operator"
+	// defaultTerminationGracePeriod is the termination grace period of kubernetes if it's not set
+	defaultTerminationGracePeriod = 30 * time.Second
+	// prestopTimeoutMargin is left for tiflow to exit after the preStop hook returns
+	prestopTimeoutMargin = 5 * time.Second
+)
+
+// prestopInitContainer installs the operator binary which handles the preStop hook of tiflow components,
+// the tiflow image doesn't ship it.
+func prestopInitContainer() corev1.Container {
+	return corev1.Container{
+		Name:            prestopVolumeName,
+		Image:           controller.DiscoveryImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"/manager", "prestop", "install", "--dir", prestopBinDir},
+		VolumeMounts:    []corev1.VolumeMount{{Name: prestopVolumeName, MountPath: prestopBinDir}},
+	}
+}
+
+// keepPrestopImage keeps the image of the init container applied last time unless the template is changed otherwise,
+// so upgrading the operator doesn't roll the pods of every cluster it manages. The installed binary is refreshed
+// together with the next change of the template.
+func keepPrestopImage(newSts, oldSts *apps.StatefulSet) {
+	_, podSpec, err := GetLastAppliedConfig(oldSts)
+	if err != nil {
+		return
+	}
+	var image string
+	for _, c := range podSpec.InitContainers {
+		if c.Name == prestopVolumeName {
+			image = c.Image
+		}
+	}
+	for i := range newSts.Spec.Template.Spec.InitContainers {
+		c := &newSts.Spec.Template.Spec.InitContainers[i]
+		if c.Name != prestopVolumeName || image == "" || c.Image == image {
+			continue
+		}
+		desired := c.Image
+		c.Image = image
+		if !templateEqual(newSts, oldSts) {
+			c.Image = desired
+		}
+	}
+}
+
+func prestopVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{Name: prestopVolumeName, ReadOnly: true, MountPath: prestopBinDir}
+}
+
+func prestopVolume() corev1.Volume {
+	return corev1.Volume{
+		Name:         prestopVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	}
+}
+
+// masterPrestop resigns the leadership of the tiflow-master before it's stopped
+func masterPrestop(tc *v1alpha1.TiflowCluster, spec component.ComponentAccessor) *corev1.Lifecycle {
+	args := []string{"--tc-name", tc.GetName()}
+	if tc.IsClusterTLSEnabled() {
+		args = append(args, "--tls-cert-dir", clusterCertPath)
+	}
+	return prestopLifecycle("master", spec, args)
+}
+
+// executorPrestop drains the tiflow-executor from the tiflow-master it joins before it's stopped
+func executorPrestop(tc *v1alpha1.TiflowCluster, spec component.ComponentAccessor) *corev1.Lifecycle {
+	args := []string{"--master-url", tiflowapi.JoinedMasterURL(tc)}
+	if tc.IsClusterTLSEnabled() || executorUseRemoteTLS(tc) {
+		args = append(args, "--tls-cert-dir", clusterCertPath)
+	}
+	return prestopLifecycle("executor", spec, args)
+}
+
+// prestopLifecycle bounds the preStop hook by the termination grace period,
+// which also covers the hook, so tiflow still has a few seconds to exit
+func prestopLifecycle(comp string, spec component.ComponentAccessor, args []string) *corev1.Lifecycle {
+	timeout := defaultTerminationGracePeriod
+	if grace := spec.TerminationGracePeriodSeconds(); grace != nil {
+		timeout = time.Duration(*grace) * time.Second
+	}
+	timeout -= prestopTimeoutMargin
+	if timeout < time.Second {
+		timeout = time.Second
+	}
+
+	command := append([]string{path.Join(prestopBinDir, prestop.BinaryName), "prestop", comp,
+		fmt.Sprintf("--timeout=%s", timeout)}, args...)
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: command},
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package member
+
+import (
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/pingcap/tiflow-operator/pkg/component"
+	"github.com/pingcap/tiflow-operator/pkg/prestop"
+)
+
+// statusPath is served by tiflow-master and tiflow-executor as soon as their HTTP server is up, it doesn't
+// depend on the leader of tiflow-master, so the members stay ready during elections
+const statusPath = "/metrics"
+
+// getComponentProbes returns the readiness, liveness and startup probes of the component container, tuned by
+// the component spec. By default they are HTTP GETs against the status endpoint on port. If tlsEnabled, the
+// component requires client certificates which kubelet can't present, so the endpoint is requested by the
+// operator binary installed in the pod with the certificates mounted at clusterCertPath, and the liveness and
+// startup probes pass while the pod is in debug mode.
+func getComponentProbes(spec component.ComponentAccessor, port int, tlsEnabled bool) (readiness, liveness, startup *corev1.Probe) {
+	handler := func(passInDebug bool) corev1.ProbeHandler {
+		if !tlsEnabled {
+			return corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   statusPath,
+					Port:   intstr.FromInt(port),
+					Scheme: corev1.URISchemeHTTP,
+				},
+			}
+		}
+		command := []string{path.Join(prestopBinDir, prestop.BinaryName), "probe",
+			fmt.Sprintf("--port=%d", port), fmt.Sprintf("--path=%s", statusPath),
+			fmt.Sprintf("--tls-cert-dir=%s", clusterCertPath)}
+		if passInDebug {
+			command = append(command, fmt.Sprintf("--annotations-file=%s", annotationsFilePath))
+		}
+		return corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: command}}
+	}
+
+	readiness = mergeProbe(&corev1.Probe{
+		ProbeHandler:     handler(false),
+		PeriodSeconds:    5,
+		TimeoutSeconds:   3,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}, spec.ReadinessProbe())
+	liveness = mergeProbe(&corev1.Probe{
+		ProbeHandler:     handler(true),
+		PeriodSeconds:    10,
+		TimeoutSeconds:   5,
+		SuccessThreshold: 1,
+		FailureThreshold: 3,
+	}, spec.LivenessProbe())
+	// give the tiflow server 5 minutes to start before the liveness probe takes over
+	startup = mergeProbe(&corev1.Probe{
+		ProbeHandler:     handler(true),
+		PeriodSeconds:    5,
+		TimeoutSeconds:   3,
+		SuccessThreshold: 1,
+		FailureThreshold: 60,
+	}, spec.StartupProbe())
+	return readiness, liveness, startup
+}
+
+// mergeProbe overrides the default probe with the non-zero fields of the user-defined one
+func mergeProbe(probe, override *corev1.Probe) *corev1.Probe {
+	if override == nil {
+		return probe
+	}
+	if override.Exec != nil || override.HTTPGet != nil || override.TCPSocket != nil || override.GRPC != nil {
+		probe.ProbeHandler = *override.ProbeHandler.DeepCopy()
+	}
+	if override.InitialDelaySeconds != 0 {
+		probe.InitialDelaySeconds = override.InitialDelaySeconds
+	}
+	if override.TimeoutSeconds != 0 {
+		probe.TimeoutSeconds = override.TimeoutSeconds
+	}
+	if override.PeriodSeconds != 0 {
+		probe.PeriodSeconds = override.PeriodSeconds
+	}
+	if override.SuccessThreshold != 0 {
+		probe.SuccessThreshold = override.SuccessThreshold
+	}
+	if override.FailureThreshold != 0 {
+		probe.FailureThreshold = override.FailureThreshold
+	}
+	if override.TerminationGracePeriodSeconds != nil {
+		probe.TerminationGracePeriodSeconds = override.TerminationGracePeriodSeconds
+	}
+	return probe
+}
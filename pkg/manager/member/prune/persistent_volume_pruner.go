@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/pingcap/tiflow-operator/api/label"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/event"
+	"github.com/pingcap/tiflow-operator/pkg/metrics"
 	"k8s.io/apimachinery/pkg/watch"
 	"sort"
 	"strings"
@@ -12,8 +15,10 @@ import (
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
@@ -22,13 +27,15 @@ import (
 // The underlying PVs SHOULD have their reclaim policy set to delete.
 type PersistentVolumePruner struct {
 	ClientSet kubernetes.Interface
+	Recorder  record.EventRecorder
 }
 
 // NewPersistentVolumePruner return a new PersistentVolumePruner
-func NewPersistentVolumePruner(clientSet kubernetes.Interface) PVCPruner {
+func NewPersistentVolumePruner(clientSet kubernetes.Interface, recorder record.EventRecorder) PVCPruner {
 
 	return &PersistentVolumePruner{
 		clientSet,
+		recorder,
 	}
 }
 
@@ -242,8 +249,73 @@ func (p *PersistentVolumePruner) pvcsToRemove(ctx context.Context, tc *v1alpha1.
 		}); err != nil {
 			return fmt.Errorf("delting PVC %s err, error: %v", pvc.Name, err)
 		}
+		p.Recorder.Eventf(tc, corev1.EventTypeNormal, event.PVCPruned, "prune unused PVC %s of tiflow-executor", pvc.Name)
+		metrics.PVCsPruned.WithLabelValues(tc.GetNamespace(), tc.GetName()).Inc()
 
 	}
 
 	return nil
 }
+
+// Reclaim deletes all executor PVCs of the tiflowCluster if its PVReclaimPolicy is Delete,
+// otherwise the PVCs are orphaned from the tiflowCluster, so that they will not be
+// removed by the garbage collector together with it.
+func (p *PersistentVolumePruner) Reclaim(ctx context.Context, tc *v1alpha1.TiflowCluster) error {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	if tc.Spec.Executor == nil {
+		return nil
+	}
+
+	selector, err := label.New().Instance(tc.GetInstanceName()).TiflowExecutor().Selector()
+	if err != nil {
+		return fmt.Errorf("building PVC selector of [%s/%s] error: %v", ns, tcName, err)
+	}
+	pvcs, err := p.ClientSet.CoreV1().PersistentVolumeClaims(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("listing PVCs of [%s/%s] to reclaim error: %v", ns, tcName, err)
+	}
+
+	policy := corev1.PersistentVolumeReclaimRetain
+	if tc.Spec.Executor.PVReclaimPolicy != nil {
+		policy = *tc.Spec.Executor.PVReclaimPolicy
+	}
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if policy == corev1.PersistentVolumeReclaimDelete {
+			klog.Infof("reclaiming PVC for [%s/%s], deleting PVC: %s", ns, tcName, pvc.Name)
+			if err := p.ClientSet.CoreV1().PersistentVolumeClaims(ns).Delete(ctx, pvc.Name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{
+					UID: &pvc.UID,
+				},
+			}); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("deleting PVC %s err, error: %v", pvc.Name, err)
+			}
+			p.Recorder.Eventf(tc, corev1.EventTypeNormal, event.PVCReclaimed, "delete PVC %s of tiflow-executor as PVReclaimPolicy is %s", pvc.Name, policy)
+			continue
+		}
+
+		refs := make([]metav1.OwnerReference, 0, len(pvc.OwnerReferences))
+		for _, ref := range pvc.OwnerReferences {
+			if ref.UID != tc.GetUID() {
+				refs = append(refs, ref)
+			}
+		}
+		if len(refs) == len(pvc.OwnerReferences) {
+			continue
+		}
+
+		klog.Infof("reclaiming PVC for [%s/%s], retaining PVC: %s", ns, tcName, pvc.Name)
+		pvc.OwnerReferences = refs
+		if _, err := p.ClientSet.CoreV1().PersistentVolumeClaims(ns).Update(ctx, pvc, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("retaining PVC %s err, error: %v", pvc.Name, err)
+		}
+		p.Recorder.Eventf(tc, corev1.EventTypeNormal, event.PVCReclaimed, "retain PVC %s of tiflow-executor as PVReclaimPolicy is %s", pvc.Name, policy)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package prune
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestReclaim(t *testing.T) {
+	ctx := context.Background()
+	tc := &v1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo", UID: "tc-uid"},
+		Spec:       v1alpha1.TiflowClusterSpec{Executor: &v1alpha1.ExecutorSpec{}},
+	}
+	newPVC := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-1",
+			Name:      name,
+			Labels:    label.New().Instance("demo").TiflowExecutor().Labels(),
+			OwnerReferences: []metav1.OwnerReference{
+				{UID: "tc-uid", Name: "demo"},
+				{UID: "other-uid", Name: "other"},
+			},
+		}}
+	}
+	clientSet := fake.NewSimpleClientset(newPVC("data-demo-tiflow-executor-0"), newPVC("data-demo-tiflow-executor-1"))
+	recorder := record.NewFakeRecorder(10)
+	pruner := NewPersistentVolumePruner(clientSet, recorder)
+
+	// PVCs are retained by default, they are released from the tiflow cluster
+	require.NoError(t, pruner.Reclaim(ctx, tc))
+	pvcs, err := clientSet.CoreV1().PersistentVolumeClaims("ns-1").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pvcs.Items, 2)
+	for _, pvc := range pvcs.Items {
+		require.Len(t, pvc.OwnerReferences, 1)
+		require.Equal(t, "other", pvc.OwnerReferences[0].Name)
+	}
+	require.Len(t, recorder.Events, 2)
+
+	// released PVCs are left alone
+	require.NoError(t, pruner.Reclaim(ctx, tc))
+	require.Len(t, recorder.Events, 2)
+
+	policy := corev1.PersistentVolumeReclaimDelete
+	tc.Spec.Executor.PVReclaimPolicy = &policy
+	require.NoError(t, pruner.Reclaim(ctx, tc))
+	pvcs, err = clientSet.CoreV1().PersistentVolumeClaims("ns-1").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pvcs.Items)
+	require.Len(t, recorder.Events, 4)
+}
@@ -7,4 +7,6 @@ import (
 
 type PVCPruner interface {
 	Prune(ctx context.Context, tc *v1alpha1.TiflowCluster) error
+	// Reclaim handles all PVCs of a tiflowCluster which is being deleted according to its PVReclaimPolicy
+	Reclaim(ctx context.Context, tc *v1alpha1.TiflowCluster) error
 }
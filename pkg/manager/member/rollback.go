@@ -0,0 +1,198 @@
+package member
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/event"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+	"github.com/pingcap/tiflow-operator/pkg/status"
+)
+
+// upgradeRollback records the revision a component is upgraded from,
+// and rolls the statefulSet template back to it if the upgraded pods keep failing.
+type upgradeRollback struct {
+	cli      client.Client
+	recorder record.EventRecorder
+	tc       *v1alpha1.TiflowCluster
+	member   v1alpha1.MemberType
+}
+
+func newUpgradeRollback(cli client.Client, recorder record.EventRecorder, tc *v1alpha1.TiflowCluster, member v1alpha1.MemberType) *upgradeRollback {
+	return &upgradeRollback{
+		cli:      cli,
+		recorder: recorder,
+		tc:       tc,
+		member:   member,
+	}
+}
+
+func (r *upgradeRollback) record() *v1alpha1.UpgradeRecord {
+	if r.member == v1alpha1.TiFlowMasterMemberType {
+		return r.tc.Status.Master.Upgrade
+	}
+	return r.tc.Status.Executor.Upgrade
+}
+
+func (r *upgradeRollback) setRecord(rec *v1alpha1.UpgradeRecord) {
+	if r.member == v1alpha1.TiFlowMasterMemberType {
+		r.tc.Status.Master.Upgrade = rec
+		return
+	}
+	r.tc.Status.Executor.Upgrade = rec
+}
+
+// start records the revision and image the component is upgraded from, once per UpdateRevision
+func (r *upgradeRollback) start(stsStatus *apps.StatefulSetStatus) {
+	if rec := r.record(); rec != nil && rec.ToRevision == stsStatus.UpdateRevision {
+		return
+	}
+
+	rec := &v1alpha1.UpgradeRecord{
+		FromRevision: stsStatus.CurrentRevision,
+		ToRevision:   stsStatus.UpdateRevision,
+		StartTime:    metav1.Now(),
+	}
+	template, err := r.revisionTemplate(stsStatus.CurrentRevision)
+	if err != nil {
+		klog.Warningf("tiflowcluster: [%s/%s]'s %s failed to get the template of revision %s, error: %v",
+			r.tc.GetNamespace(), r.tc.GetName(), r.member, stsStatus.CurrentRevision, err)
+	} else {
+		for _, c := range template.Spec.Containers {
+			if c.Name == r.member.String() {
+				rec.FromImage = c.Image
+			}
+		}
+	}
+	r.setRecord(rec)
+}
+
+// finish forgets the upgrade which is completed
+func (r *upgradeRollback) finish() {
+	r.setRecord(nil)
+}
+
+// held returns true if the generated template has been rolled back before, then the applied template is kept,
+// and the pods still failing in the rolled back revision are deleted to be recreated in the old revision.
+// The template is upgraded again once the spec of the component is changed.
+func (r *upgradeRollback) held(oldSts, newSts *apps.StatefulSet, stsStatus *apps.StatefulSetStatus) (bool, error) {
+	rec := r.record()
+	if !rec.RolledBack() {
+		return false, nil
+	}
+	hash, err := mngerutils.Sha256Sum(newSts.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+	if hash != rec.RolledBackTemplateHash {
+		r.setRecord(nil)
+		return false, nil
+	}
+
+	newSts.Spec.Template = *oldSts.Spec.Template.DeepCopy()
+	if newSts.Spec.UpdateStrategy.Type == apps.RollingUpdateStatefulSetStrategyType {
+		mngerutils.SetUpgradePartition(newSts, 0)
+	}
+	if stsStatus.UpdateRevision != rec.FromRevision {
+		// the rolled back template is not observed by the statefulSet controller yet
+		return true, nil
+	}
+	// the statefulSet controller won't replace the failed pods itself if pods are managed in order
+	for i := int32(0); i < *oldSts.Spec.Replicas; i++ {
+		pod := &corev1.Pod{}
+		err := r.cli.Get(context.TODO(), types.NamespacedName{
+			Namespace: r.tc.GetNamespace(),
+			Name:      fmt.Sprintf("%s-%d", oldSts.GetName(), i),
+		}, pod)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return true, err
+		}
+		if pod.Labels[apps.ControllerRevisionHashLabelKey] != rec.ToRevision || pod.DeletionTimestamp != nil || podutil.IsPodReady(pod) {
+			continue
+		}
+		klog.Infof("tiflowcluster: [%s/%s]'s %s delete pod %s of rolled back revision %s",
+			r.tc.GetNamespace(), r.tc.GetName(), r.member, pod.GetName(), rec.ToRevision)
+		if err := r.cli.Delete(context.TODO(), pod); err != nil && !errors.IsNotFound(err) {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// failingSince returns the time since which the upgraded pod has been in its current state
+func (r *upgradeRollback) failingSince(pod *corev1.Pod) time.Time {
+	since := pod.CreationTimestamp.Time
+	if rec := r.record(); rec != nil && rec.StartTime.After(since) {
+		since = rec.StartTime.Time
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.LastTransitionTime.After(since) {
+			since = cond.LastTransitionTime.Time
+		}
+	}
+	return since
+}
+
+// timeout rolls the template of newSts back to the revision before the upgrade if the upgraded pod has been failing
+// for longer than the upgrade timeout, it returns true if the template is rolled back.
+func (r *upgradeRollback) timeout(newSts *apps.StatefulSet, pod *corev1.Pod, reason string) (bool, error) {
+	rec := r.record()
+	if rec == nil || rec.FromRevision == "" || !r.tc.AutoRollbackEnabled() {
+		return false, nil
+	}
+	timeout := r.tc.GetUpgradeTimeout()
+	if time.Since(r.failingSince(pod)) < timeout {
+		return false, nil
+	}
+
+	template, err := r.revisionTemplate(rec.FromRevision)
+	if err != nil {
+		return false, fmt.Errorf("tiflowcluster: [%s/%s]'s %s failed to roll back to revision %s, error: %v",
+			r.tc.GetNamespace(), r.tc.GetName(), r.member, rec.FromRevision, err)
+	}
+	hash, err := mngerutils.Sha256Sum(newSts.Spec.Template)
+	if err != nil {
+		return false, err
+	}
+	rec.RolledBackTemplateHash = hash
+	// all the pods are brought back to the old revision
+	newSts.Spec.Template = *template
+	mngerutils.SetUpgradePartition(newSts, 0)
+
+	msg := fmt.Sprintf("%s [%s/%s] upgrade rolled back to revision %s (image %s): pod %s %s for more than %s",
+		r.member, r.tc.GetNamespace(), r.tc.GetName(), rec.FromRevision, rec.FromImage, pod.GetName(), reason, timeout)
+	klog.Warning(msg)
+	status.Failed(v1alpha1.UpgradeType, r.tc.GetClusterStatus(), r.member, msg)
+	r.recorder.Event(r.tc, corev1.EventTypeWarning, event.RollbackUpgrade, msg)
+	return true, nil
+}
+
+// revisionTemplate returns the pod template saved in the ControllerRevision of the statefulSet
+func (r *upgradeRollback) revisionTemplate(revision string) (*corev1.PodTemplateSpec, error) {
+	cr := &apps.ControllerRevision{}
+	if err := r.cli.Get(context.TODO(), types.NamespacedName{Namespace: r.tc.GetNamespace(), Name: revision}, cr); err != nil {
+		return nil, err
+	}
+	// the revision of statefulSet is a patch which replaces the template
+	sts := &apps.StatefulSet{}
+	if err := json.Unmarshal(cr.Data.Raw, sts); err != nil {
+		return nil, err
+	}
+	return &sts.Spec.Template, nil
+}
@@ -52,9 +52,30 @@ runmode=${runmode:-normal}
 if [[ X${runmode} == Xdebug ]]
 then
     echo "entering debug mode."
-    tail -f /dev/null
+    while [[ X${runmode} == Xdebug ]]
+    do
+        sleep 5
+        runmode=normal
+        source ${ANNOTATIONS} 2>/dev/null
+    done
+    echo "leaving debug mode."
 fi
 
+# Restart the container in debug mode once runmode is changed to debug
+(
+    while sleep 5
+    do
+        runmode=normal
+        source ${ANNOTATIONS} 2>/dev/null
+        if [[ X${runmode} == Xdebug ]]
+        then
+            echo "runmode is changed to debug, restarting."
+            kill -TERM 1
+            break
+        fi
+    done
+) &
+
 # Use HOSTNAME if POD_NAME is unset for backward compatibility.
 POD_NAME=${POD_NAME:-$HOSTNAME}
 
@@ -106,17 +127,76 @@ runmode=${runmode:-normal}
 if [[ X${runmode} == Xdebug ]]
 then
     echo "entering debug mode."
-    tail -f /dev/null
+    while [[ X${runmode} == Xdebug ]]
+    do
+        sleep 5
+        runmode=normal
+        source ${ANNOTATIONS} 2>/dev/null
+    done
+    echo "leaving debug mode."
 fi
 
+# Restart the container in debug mode once runmode is changed to debug
+(
+    while sleep 5
+    do
+        runmode=normal
+        source ${ANNOTATIONS} 2>/dev/null
+        if [[ X${runmode} == Xdebug ]]
+        then
+            echo "runmode is changed to debug, restarting."
+            kill -TERM 1
+            break
+        fi
+    done
+) &
+
 # Use HOSTNAME if POD_NAME is unset for backward compatibility.
 POD_NAME=${POD_NAME:-$HOSTNAME}
 name=${POD_NAME}.${NAMESPACE}
+{{- if .AdvertiseAddresses }}
+
+# Use the externally reachable address configured for this ordinal, fall back to the in-cluster one
+ordinal=${POD_NAME##*-}
+advertise_addr=${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc{{ .FormatClusterDomain }}:10241
+i=0
+for addr in {{ .AdvertiseAddresses }}
+do
+    if [[ ${i} -eq ${ordinal} ]]
+    then
+        advertise_addr=${addr}
+    fi
+    i=$((i + 1))
+done
+{{- end }}
+{{- if .DiscoveryAddress }}
+
+# Ask the discovery service for the alive tiflow-master members, they are joined before the static addresses,
+# which are kept for the tiflow-master of the referenced cluster unknown to the discovery service.
+# Only the static addresses are joined if the image doesn't ship wget.
+join={{ .MasterAddress }}
+if command -v wget >/dev/null 2>&1
+then
+    for i in 1 2 3
+    do
+        masters=$(wget -q -T 3 -O - http://{{ .DiscoveryAddress }}/masters 2>/dev/null)
+        if [[ -n "${masters}" ]]
+        then
+            join=${masters},{{ .MasterAddress }}
+            break
+        fi
+        echo "failed to get tiflow-master members from discovery service {{ .DiscoveryAddress }}, retry ${i}"
+        sleep 2
+    done
+else
+    echo "wget is not found, skip asking discovery service {{ .DiscoveryAddress }}"
+fi
+{{- end }}
 
 ARGS="--name $name \
---join={{ .MasterAddress }} \
+--join={{ if .DiscoveryAddress }}${join}{{ else }}{{ .MasterAddress }}{{ end }} \
 --addr=:10241 \
---advertise-addr=${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc{{ .FormatClusterDomain }}:10241 \
+--advertise-addr={{ if .AdvertiseAddresses }}${advertise_addr}{{ else }}${POD_NAME}.${PEER_SERVICE_NAME}.${NAMESPACE}.svc{{ .FormatClusterDomain }}:10241{{ end }} \
 --config={{ .DataDir }}/tiflow-executor.toml \
 "
 
@@ -129,6 +209,10 @@ type TiflowExecutorStartScriptModel struct {
 	CommonModel
 	DataDir       string
 	MasterAddress string
+	// AdvertiseAddresses are space separated addresses advertised by executors in order of ordinal
+	AdvertiseAddresses string
+	// DiscoveryAddress is the address of discovery service which tells the alive tiflow-master members
+	DiscoveryAddress string
 }
 
 func RenderExecutorStartScript(model *TiflowExecutorStartScriptModel) (string, error) {
@@ -1,6 +1,46 @@
 package member
 
+import (
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pingcap/tiflow-operator/api/config"
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
 var (
 	clusterCertPath = "/var/lib/tiflow-cluster-certs"
 	clientCertPath  = "/var/lib/tiflow-client-certs"
 )
+
+// executorUseRemoteTLS returns whether executors connect to the remote tiflow-master with its client certificates
+func executorUseRemoteTLS(tc *v1alpha1.TiflowCluster) bool {
+	return tiflowapi.RemoteMasterTLSSecretName(tc) != ""
+}
+
+// executorClusterTLSSecretName returns the secret mounted at clusterCertPath of executors
+func executorClusterTLSSecretName(tc *v1alpha1.TiflowCluster) string {
+	if executorUseRemoteTLS(tc) {
+		return tiflowapi.RemoteMasterTLSSecretName(tc)
+	}
+	return util.ClusterTLSSecretName(tc.Name, label.TiflowMasterLabelVal)
+}
+
+// setSecurityConfig makes tiflow use the certificates mounted at certDir to connect to other components
+func setSecurityConfig(cfg *config.GenericConfig, certDir string) {
+	if cfg.MP == nil {
+		cfg.MP = map[string]interface{}{}
+	}
+	security, ok := cfg.MP["security"].(map[string]interface{})
+	if !ok {
+		security = map[string]interface{}{}
+	}
+	security["ca-path"] = path.Join(certDir, corev1.ServiceAccountRootCAKey)
+	security["cert-path"] = path.Join(certDir, corev1.TLSCertKey)
+	security["key-path"] = path.Join(certDir, corev1.TLSPrivateKeyKey)
+	cfg.MP["security"] = security
+}
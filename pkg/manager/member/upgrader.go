@@ -1,8 +1,15 @@
 package member
 
 import (
-	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"fmt"
+	"time"
+
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/status"
 )
 
 // Upgrader implements the logic for upgrading the tiflow cluster.
@@ -10,3 +17,63 @@ type Upgrader interface {
 	// Upgrade upgrade the cluster
 	Upgrade(*v1alpha1.TiflowCluster, *apps.StatefulSet, *apps.StatefulSet) error
 }
+
+// upgradeProgress records the pods upgraded by the rolling upgrade of a component,
+// and reports them with the reason of waiting in the message of UpgradeType.
+type upgradeProgress struct {
+	tc       *v1alpha1.TiflowCluster
+	member   v1alpha1.MemberType
+	spec     *v1alpha1.UpgradeSpec
+	replicas int32
+
+	upgraded []int32
+	// readySince is the latest time an upgraded pod became ready
+	readySince time.Time
+}
+
+func newUpgradeProgress(tc *v1alpha1.TiflowCluster, member v1alpha1.MemberType, spec *v1alpha1.UpgradeSpec, replicas int32) *upgradeProgress {
+	return &upgradeProgress{
+		tc:       tc,
+		member:   member,
+		spec:     spec,
+		replicas: replicas,
+	}
+}
+
+// podUpgraded records the pod which is upgraded and ready
+func (p *upgradeProgress) podUpgraded(ordinal int32, pod *corev1.Pod) {
+	p.upgraded = append(p.upgraded, ordinal)
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.LastTransitionTime.After(p.readySince) {
+			p.readySince = cond.LastTransitionTime.Time
+		}
+	}
+}
+
+// wait reports why the upgrade doesn't move on
+func (p *upgradeProgress) wait(format string, args ...interface{}) {
+	status.Ongoing(v1alpha1.UpgradeType, p.tc.GetClusterStatus(), p.member,
+		fmt.Sprintf("%s [%s/%s] upgrading, upgraded ordinals: %v, waiting for %s",
+			p.member, p.tc.GetNamespace(), p.tc.GetName(), p.upgraded, fmt.Sprintf(format, args...)))
+}
+
+// hold returns true if the pod with ordinal should not be upgraded yet according to the upgrade spec,
+// a RequeueError is returned as well if the upgrade will move on by itself later.
+func (p *upgradeProgress) hold(ordinal int32) (bool, error) {
+	if partition := p.spec.GetPartition(p.replicas); ordinal < partition {
+		p.wait("partition to be lowered, pods with ordinal below %d are kept in the old revision", partition)
+		return true, nil
+	}
+	if p.spec.IsPaused() {
+		p.wait("upgrade to be resumed")
+		return true, nil
+	}
+	if period := p.spec.GetObservationPeriod(); period > 0 && !p.readySince.IsZero() {
+		if until := p.readySince.Add(period); time.Now().Before(until) {
+			p.wait("observation of upgraded pods until %s", until.UTC().Format(time.RFC3339))
+			return true, controller.RequeueErrorf("tiflowcluster: [%s/%s]'s %s upgraded pods are observed until %s",
+				p.tc.GetNamespace(), p.tc.GetName(), p.member, until.UTC().Format(time.RFC3339))
+		}
+	}
+	return false, nil
+}
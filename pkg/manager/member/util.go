@@ -4,31 +4,49 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	perrors "github.com/pingcap/errors"
 	apps "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/pingcap/tiflow-operator/api/label"
 	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/condition"
 	"github.com/pingcap/tiflow-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+	"github.com/pingcap/tiflow-operator/pkg/status"
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+	"github.com/pingcap/tiflow-operator/pkg/util"
 )
 
 const (
 	LastAppliedConfigAnnotation = "pingcap.com/last-applied-configuration"
 	extractPodIDRegexStr        = "(.*)-([\\d]+)\\.(.*)"
+	// annotationsFilePath is the downward API file of the pod annotations read by the start scripts
+	annotationsFilePath = "/etc/podinfo/annotations"
 )
 
 var extracPodIDRegex = regexp.MustCompile(extractPodIDRegexStr)
 
+// getMasterClient provides the MasterClient of tiflow-master, it's replaced by tests
+var getMasterClient = tiflowapi.GetMasterClient
+
 func getNodePort(svc *v1alpha1.ServiceSpec) int32 {
 	if svc.NodePort != nil {
 		return *svc.NodePort
@@ -36,6 +54,90 @@ func getNodePort(svc *v1alpha1.ServiceSpec) int32 {
 	return 0
 }
 
+// applyServiceSpec overrides fields of svc with the user-defined ServiceSpec,
+// the fields which can't be shared by several services are skipped if shared is false.
+func applyServiceSpec(svc *corev1.Service, svcSpec *v1alpha1.ServiceSpec, shared bool) {
+	if svcSpec.Type != "" {
+		svc.Spec.Type = svcSpec.Type
+	}
+	svc.ObjectMeta.Annotations = util.CopyStringMap(svcSpec.Annotations)
+	svc.ObjectMeta.Labels = util.CombineStringMap(svc.ObjectMeta.Labels, svcSpec.Labels)
+	if svcSpec.Type == corev1.ServiceTypeLoadBalancer {
+		if shared && svcSpec.LoadBalancerIP != nil {
+			svc.Spec.LoadBalancerIP = *svcSpec.LoadBalancerIP
+		}
+		if svcSpec.LoadBalancerSourceRanges != nil {
+			svc.Spec.LoadBalancerSourceRanges = svcSpec.LoadBalancerSourceRanges
+		}
+	}
+	if svcSpec.ExternalTrafficPolicy != nil {
+		svc.Spec.ExternalTrafficPolicy = *svcSpec.ExternalTrafficPolicy
+	}
+	if shared {
+		svc.Spec.Ports[0].NodePort = getNodePort(svcSpec)
+		if svcSpec.ClusterIP != nil {
+			svc.Spec.ClusterIP = *svcSpec.ClusterIP
+		}
+	}
+	if svcSpec.PortName != nil {
+		svc.Spec.Ports[0].Name = *svcSpec.PortName
+	}
+}
+
+// syncService creates the service or updates it if its spec differs from the last applied config
+func syncService(ctx context.Context, cli client.Client, newSvc *corev1.Service) error {
+	oldSvc := &corev1.Service{}
+	err := cli.Get(ctx, client.ObjectKeyFromObject(newSvc), oldSvc)
+	if errors.IsNotFound(err) {
+		if err = controller.SetServiceLastAppliedConfigAnnotation(newSvc); err != nil {
+			return err
+		}
+		return cli.Create(ctx, newSvc)
+	}
+	if err != nil {
+		return fmt.Errorf("syncService: failed to get svc %s/%s, error: %v", newSvc.Namespace, newSvc.Name, err)
+	}
+
+	util.RetainManagedFields(newSvc, oldSvc)
+	equal, err := controller.ServiceEqual(newSvc, oldSvc)
+	if err != nil {
+		return err
+	}
+	if equal {
+		return nil
+	}
+
+	svc := oldSvc.DeepCopy()
+	svc.Spec = newSvc.Spec
+	svc.Labels = newSvc.Labels
+	if err = controller.SetServiceLastAppliedConfigAnnotation(svc); err != nil {
+		return err
+	}
+	// keep the cluster ip allocated by kubernetes
+	svc.Spec.ClusterIP = oldSvc.Spec.ClusterIP
+	svc.Spec.ClusterIPs = oldSvc.Spec.ClusterIPs
+	for k, v := range newSvc.Annotations {
+		svc.Annotations[k] = v
+	}
+	return cli.Update(ctx, svc)
+}
+
+// deleteOwnedService deletes the service if it's controlled by the tiflow cluster
+func deleteOwnedService(ctx context.Context, cli client.Client, tc *v1alpha1.TiflowCluster, name string) error {
+	svc := &corev1.Service{}
+	err := cli.Get(ctx, types.NamespacedName{Namespace: tc.GetNamespace(), Name: name}, svc)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("deleteOwnedService: failed to get svc %s/%s, error: %v", tc.GetNamespace(), name, err)
+	}
+	if !metav1.IsControlledBy(svc, tc) {
+		return nil
+	}
+	return client.IgnoreNotFound(cli.Delete(ctx, svc))
+}
+
 // TODO: check whether do we need this func
 // getStsAnnotations gets annotations for statefulset of given component.
 // func getStsAnnotations(tcAnns map[string]string, component string) map[string]string {
@@ -62,14 +164,14 @@ func getNodePort(svc *v1alpha1.ServiceSpec) int32 {
 // }
 
 func annotationsMountVolume() (corev1.VolumeMount, corev1.Volume) {
-	m := corev1.VolumeMount{Name: "annotations", ReadOnly: true, MountPath: "/etc/podinfo"}
+	m := corev1.VolumeMount{Name: "annotations", ReadOnly: true, MountPath: path.Dir(annotationsFilePath)}
 	v := corev1.Volume{
 		Name: "annotations",
 		VolumeSource: corev1.VolumeSource{
 			DownwardAPI: &corev1.DownwardAPIVolumeSource{
 				Items: []corev1.DownwardAPIVolumeFile{
 					{
-						Path:     "annotations",
+						Path:     path.Base(annotationsFilePath),
 						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.annotations"},
 					},
 				},
@@ -79,6 +181,29 @@ func annotationsMountVolume() (corev1.VolumeMount, corev1.Volume) {
 	return m, v
 }
 
+// keepBlockedVersion keeps the image of the running statefulSet if the desired version of the member
+// can't be rolled out yet, the reason is reported by the VersionChecked condition.
+func keepBlockedVersion(tc *v1alpha1.TiflowCluster, memberType v1alpha1.MemberType, newSts, oldSts *apps.StatefulSet) {
+	reason, message := mngerutils.CheckVersion(tc, memberType)
+	if reason == "" {
+		return
+	}
+	klog.Infof("tiflowcluster: [%s/%s]'s %s version is kept: %s", tc.GetNamespace(), tc.GetName(), memberType, message)
+	condition.SetFalseWithReason(v1alpha1.VersionChecked, tc.GetClusterStatus(), reason, message, metav1.Now())
+
+	var image string
+	for _, c := range oldSts.Spec.Template.Spec.Containers {
+		if c.Name == memberType.String() {
+			image = c.Image
+		}
+	}
+	for i := range newSts.Spec.Template.Spec.Containers {
+		if c := &newSts.Spec.Template.Spec.Containers[i]; c.Name == memberType.String() && image != "" {
+			c.Image = image
+		}
+	}
+}
+
 // NeedForceUpgrade check if force upgrade is necessary
 func NeedForceUpgrade(ann map[string]string) bool {
 	// Check if annotation 'pingcap.com/force-upgrade: "true"' is set
@@ -91,6 +216,61 @@ func NeedForceUpgrade(ann map[string]string) bool {
 	return false
 }
 
+// NeedForceDelete check if running jobs should be canceled when the cluster is deleted
+func NeedForceDelete(ann map[string]string) bool {
+	// Check if annotation 'tiflow.pingcap.com/force-delete: "true"' is set
+	if ann != nil {
+		forceVal, ok := ann[label.AnnForceDeleteKey]
+		if ok && (forceVal == label.AnnForceDeleteVal) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedResume returns whether the member of tiflow cluster was suspended and should be resumed now
+func NeedResume(tc *v1alpha1.TiflowCluster, memberType v1alpha1.MemberType) bool {
+	if tc.IsPaused() {
+		return false
+	}
+	return status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), memberType) != v1alpha1.Unknown
+}
+
+// completeSuspend marks suspending the member of tiflow cluster as completed once
+func completeSuspend(tc *v1alpha1.TiflowCluster, memberType v1alpha1.MemberType, message string) {
+	if status.GetSyncStatus(v1alpha1.SuspendType, tc.GetClusterStatus(), memberType) != v1alpha1.Completed {
+		status.Completed(v1alpha1.SuspendType, tc.GetClusterStatus(), memberType, message)
+	}
+}
+
+// scaleStatefulSetToZero sets the replicas of statefulSet to zero and returns whether all pods of it are gone
+func scaleStatefulSetToZero(ctx context.Context, clientSet kubernetes.Interface, sts *apps.StatefulSet) (bool, error) {
+	if sts.Spec.Replicas == nil || *sts.Spec.Replicas != 0 {
+		return false, scaleStatefulSet(ctx, clientSet, sts, 0)
+	}
+
+	return sts.Status.Replicas == 0, nil
+}
+
+// scaleStatefulSet sets the replicas of statefulSet through the scale subresource,
+// so the last applied config of it is left untouched.
+func scaleStatefulSet(ctx context.Context, clientSet kubernetes.Interface, sts *apps.StatefulSet, replicas int32) error {
+	klog.Infof("scaling statefulSet %s/%s to %d", sts.GetNamespace(), sts.GetName(), replicas)
+	_, err := clientSet.AppsV1().StatefulSets(sts.Namespace).UpdateScale(ctx, sts.Name, &autoscaling.Scale{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sts.Name,
+			Namespace: sts.Namespace,
+		},
+		Spec: autoscaling.ScaleSpec{
+			Replicas: replicas,
+		},
+	}, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to scale statefulSet %s/%s to %d, error: %v", sts.GetNamespace(), sts.GetName(), replicas, err)
+	}
+	return nil
+}
+
 // templateEqual compares the new podTemplateSpec's spec with old podTemplateSpec's last applied config
 func templateEqual(new *apps.StatefulSet, old *apps.StatefulSet) bool {
 	oldStsSpec := apps.StatefulSetSpec{}
@@ -101,7 +281,8 @@ func templateEqual(new *apps.StatefulSet, old *apps.StatefulSet) bool {
 			klog.Errorf("unmarshal PodTemplate: [%s/%s]'s applied config failed,error: %v", old.GetNamespace(), old.GetName(), err)
 			return false
 		}
-		return apiequality.Semantic.DeepEqual(oldStsSpec.Template.Spec, new.Spec.Template.Spec)
+		return apiequality.Semantic.DeepEqual(oldStsSpec.Template.Spec, new.Spec.Template.Spec) &&
+			oldStsSpec.Template.Annotations[label.AnnRestartedAtKey] == new.Spec.Template.Annotations[label.AnnRestartedAtKey]
 	}
 	return false
 }
@@ -123,6 +304,44 @@ func TiflowMasterPeerSvcName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s.%s:%d", TiflowMasterPodName(tcName, ordinal), controller.TiflowExecutorPeerMemberName(tcName), masterPort)
 }
 
+// unhealthyMasterMembers returns the tiflow-master members which are not registered or not healthy. The local members
+// are the pods with ordinals below replicas except skipPod, the members of peer clusters are all checked.
+func unhealthyMasterMembers(tc *v1alpha1.TiflowCluster, replicas int32, skipPod string) []string {
+	ns := tc.GetNamespace()
+	var unhealthy []string
+	for i := int32(0); i < replicas; i++ {
+		podName := TiflowMasterPodName(tc.GetName(), i)
+		if podName == skipPod {
+			continue
+		}
+		if member, exist := tc.Status.Master.Members[podName+"."+ns]; !exist || !member.Health {
+			unhealthy = append(unhealthy, podName)
+		}
+	}
+	for name, member := range peerMasterMembers(tc) {
+		if !member.Health {
+			unhealthy = append(unhealthy, name)
+		}
+	}
+	sort.Strings(unhealthy)
+	return unhealthy
+}
+
+// peerMasterMembers returns the tiflow-master members of the peer clusters
+func peerMasterMembers(tc *v1alpha1.TiflowCluster) map[string]v1alpha1.MasterMember {
+	ns := tc.GetNamespace()
+	localPrefix := controller.TiflowMasterMemberName(tc.GetName()) + "-"
+	peers := make(map[string]v1alpha1.MasterMember)
+	for name, member := range tc.Status.Master.PeerMembers {
+		// members of the local pods being scaled in are not peers
+		if strings.HasPrefix(name, localPrefix) && strings.HasSuffix(name, "."+ns) {
+			continue
+		}
+		peers[name] = member
+	}
+	return peers
+}
+
 func TiflowExecutorPodName(tcName string, ordinal int32) string {
 	return fmt.Sprintf("%s-%d", controller.TiflowExecutorMemberName(tcName), ordinal)
 }
@@ -180,6 +399,26 @@ func getStsAnnotations(tcAnns map[string]string, component string) map[string]st
 	panic("implement me")
 }
 
+// keepLastAppliedTemplate keeps the pod spec and the restart stamp of the template applied last time,
+// so the pods are not restarted by the update of newSts
+func keepLastAppliedTemplate(newSts, oldSts *apps.StatefulSet) error {
+	spec, podSpec, err := GetLastAppliedConfig(oldSts)
+	if err != nil {
+		return err
+	}
+	newSts.Spec.Template.Spec = *podSpec
+	restartedAt, ok := spec.Template.Annotations[label.AnnRestartedAtKey]
+	if !ok {
+		delete(newSts.Spec.Template.Annotations, label.AnnRestartedAtKey)
+		return nil
+	}
+	if newSts.Spec.Template.Annotations == nil {
+		newSts.Spec.Template.Annotations = map[string]string{}
+	}
+	newSts.Spec.Template.Annotations[label.AnnRestartedAtKey] = restartedAt
+	return nil
+}
+
 // GetLastAppliedConfig get last applied config info from Statefulset's annotation and the podTemplate's annotation
 func GetLastAppliedConfig(set *apps.StatefulSet) (*apps.StatefulSetSpec, *corev1.PodSpec, error) {
 	specAppliedConfig, ok := set.Annotations[LastAppliedConfigAnnotation]
@@ -208,3 +447,21 @@ func getOrdinalFromName(name string, memberType v1alpha1.MemberType) (string, in
 	}
 	return strings.TrimSuffix(results[1], "-"+memberType.String()), int32(ordinal), results[3], nil
 }
+
+// validateHostPorts returns an error unless every address is a host:port, the addresses given by users
+// are rendered into start scripts, so they must not carry anything interpreted by the shell.
+func validateHostPorts(field string, addrs []string) error {
+	for _, addr := range addrs {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return fmt.Errorf("%s: invalid address %q: %v", field, addr, err)
+		}
+		if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+			return fmt.Errorf("%s: invalid port of address %q", field, addr)
+		}
+		if net.ParseIP(host) == nil && len(validation.IsDNS1123Subdomain(strings.ToLower(host))) > 0 {
+			return fmt.Errorf("%s: invalid host of address %q", field, addr)
+		}
+	}
+	return nil
+}
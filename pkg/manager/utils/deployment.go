@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/json"
+
+	apps "k8s.io/api/apps/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
+// SetDeploymentLastAppliedConfigAnnotation set last applied config to Deployment's annotation
+func SetDeploymentLastAppliedConfigAnnotation(deploy *apps.Deployment) error {
+	deployApply, err := util.Encode(deploy.Spec)
+	if err != nil {
+		return err
+	}
+	if deploy.Annotations == nil {
+		deploy.Annotations = map[string]string{}
+	}
+	deploy.Annotations[LastAppliedConfigAnnotation] = deployApply
+	return nil
+}
+
+// DeploymentEqual compares the new Deployment's spec with old Deployment's last applied config
+func DeploymentEqual(new, old *apps.Deployment) bool {
+	oldSpec := apps.DeploymentSpec{}
+	lastAppliedConfig, ok := old.Annotations[LastAppliedConfigAnnotation]
+	if !ok {
+		return false
+	}
+	err := json.Unmarshal([]byte(lastAppliedConfig), &oldSpec)
+	if err != nil {
+		klog.Errorf("unmarshal Deployment: [%s/%s]'s applied config failed, error: %v", old.GetNamespace(), old.GetName(), err)
+		return false
+	}
+	return apiequality.Semantic.DeepEqual(oldSpec, new.Spec) &&
+		apiequality.Semantic.DeepEqual(old.Labels, new.Labels)
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/version"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+// Reasons of the VersionChecked condition when the desired version of a component can't be rolled out
+const (
+	// VersionDowngradeRefused means the component is asked to be downgraded without being forced
+	VersionDowngradeRefused = "DowngradeRefused"
+	// VersionMasterNotUpgraded means tiflow-executors are asked to be newer than tiflow-masters
+	VersionMasterNotUpgraded = "MasterNotUpgraded"
+	// VersionMasterUpgrading means tiflow-executors wait for the rolling upgrade of tiflow-masters
+	VersionMasterUpgrading = "MasterUpgrading"
+	// VersionExecutorNotDowngraded means tiflow-masters are asked to be older than tiflow-executors
+	VersionExecutorNotDowngraded = "ExecutorNotDowngraded"
+	// VersionSkewExceeded means tiflow-executors would lag behind tiflow-masters by too many minor versions
+	VersionSkewExceeded = "VersionSkewExceeded"
+)
+
+// ImageVersion returns the tag of the image, empty if the image has no tag
+func ImageVersion(image string) string {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// CheckVersion returns the reason and the message if the desired version of the member can't be rolled out,
+// an empty reason means the version is allowed. The running versions are taken from the images in status.
+// Masters are upgraded before executors and downgraded after them, and executors may lag behind masters
+// by at most MaxVersionSkew minor versions of the same major. Versions which are not semantic versions are not checked.
+func CheckVersion(tc *v1alpha1.TiflowCluster, memberType v1alpha1.MemberType) (string, string) {
+	skew := tc.GetMaxVersionSkew()
+	// the version of the other component is only known if both are managed by tc
+	peerKnown := !tc.Heterogeneous() && !tc.WithoutLocalMaster() && !tc.WithoutLocalExecutor()
+
+	if memberType == v1alpha1.TiFlowMasterMemberType {
+		desired := parseVersion(tc.MasterVersion())
+		running := parseVersion(ImageVersion(tc.Status.Master.Image))
+		if desired == nil {
+			return "", ""
+		}
+		if running != nil && desired.LessThan(running) && !tc.DowngradeForced() {
+			return VersionDowngradeRefused, fmt.Sprintf("refuse to downgrade tiflow-master from %s to %s", running, desired)
+		}
+		executor := parseVersion(ImageVersion(tc.Status.Executor.Image))
+		if !peerKnown || executor == nil {
+			return "", ""
+		}
+		if desired.LessThan(executor) {
+			return VersionExecutorNotDowngraded, fmt.Sprintf("tiflow-master %s can't be older than tiflow-executor %s, downgrade tiflow-executor first",
+				desired, executor)
+		}
+		if minorSkew(desired, executor) > skew {
+			return VersionSkewExceeded, fmt.Sprintf("tiflow-master %s is more than %d minor versions ahead of tiflow-executor %s, upgrade step by step",
+				desired, skew, executor)
+		}
+		return "", ""
+	}
+
+	desired := parseVersion(tc.ExecutorVersion())
+	running := parseVersion(ImageVersion(tc.Status.Executor.Image))
+	if desired == nil {
+		return "", ""
+	}
+	if running != nil && desired.LessThan(running) && !tc.DowngradeForced() {
+		return VersionDowngradeRefused, fmt.Sprintf("refuse to downgrade tiflow-executor from %s to %s", running, desired)
+	}
+	master := parseVersion(ImageVersion(tc.Status.Master.Image))
+	if !peerKnown || master == nil {
+		return "", ""
+	}
+	if master.LessThan(desired) {
+		return VersionMasterNotUpgraded, fmt.Sprintf("tiflow-executor %s can't be newer than tiflow-master %s, upgrade tiflow-master first",
+			desired, master)
+	}
+	if running != nil && desired.String() != running.String() && masterUpgrading(tc) {
+		return VersionMasterUpgrading, fmt.Sprintf("tiflow-executor waits for tiflow-master to be upgraded to %s", master)
+	}
+	if minorSkew(master, desired) > skew {
+		return VersionSkewExceeded, fmt.Sprintf("tiflow-executor %s is more than %d minor versions behind tiflow-master %s",
+			desired, skew, master)
+	}
+	return "", ""
+}
+
+func parseVersion(v string) *version.Version {
+	parsed, err := version.ParseSemantic(v)
+	if err != nil {
+		return nil
+	}
+	return parsed
+}
+
+// minorSkew returns how many minor versions older is behind newer,
+// the skew across major versions is not limited as there is no minor version to count.
+func minorSkew(newer, older *version.Version) int {
+	if newer.Major() != older.Major() {
+		return 0
+	}
+	return int(newer.Minor()) - int(older.Minor())
+}
+
+func masterUpgrading(tc *v1alpha1.TiflowCluster) bool {
+	sts := tc.Status.Master.StatefulSet
+	if sts == nil {
+		return false
+	}
+	return sts.CurrentRevision != sts.UpdateRevision || sts.UpdatedReplicas < sts.Replicas
+}
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestImageVersion(t *testing.T) {
+	require.Equal(t, "v6.5.0", ImageVersion("pingcap/tiflow:v6.5.0"))
+	require.Equal(t, "v6.5.0", ImageVersion("localhost:5000/pingcap/tiflow:v6.5.0"))
+	require.Equal(t, "", ImageVersion("localhost:5000/pingcap/tiflow"))
+	require.Equal(t, "", ImageVersion("pingcap/tiflow"))
+}
+
+func TestCheckVersion(t *testing.T) {
+	type testcase struct {
+		name           string
+		master         string
+		executor       string
+		masterImage    string
+		executorImage  string
+		masterRolling  bool
+		forced         bool
+		masterReason   string
+		executorReason string
+	}
+	tests := []testcase{
+		{
+			name:          "up to date",
+			master:        "v6.5.0",
+			executor:      "v6.5.0",
+			masterImage:   "tiflow:v6.5.0",
+			executorImage: "tiflow:v6.5.0",
+		},
+		{
+			name:           "masters first",
+			master:         "v6.6.0",
+			executor:       "v6.6.0",
+			masterImage:    "tiflow:v6.5.0",
+			executorImage:  "tiflow:v6.5.0",
+			executorReason: VersionMasterNotUpgraded,
+		},
+		{
+			name:           "masters rolling",
+			master:         "v6.6.0",
+			executor:       "v6.6.0",
+			masterImage:    "tiflow:v6.6.0",
+			executorImage:  "tiflow:v6.5.0",
+			masterRolling:  true,
+			executorReason: VersionMasterUpgrading,
+		},
+		{
+			name:          "masters upgraded",
+			master:        "v6.6.0",
+			executor:      "v6.6.0",
+			masterImage:   "tiflow:v6.6.0",
+			executorImage: "tiflow:v6.5.0",
+		},
+		{
+			name:          "skew exceeded",
+			master:        "v6.7.0",
+			executor:      "v6.5.0",
+			masterImage:   "tiflow:v6.6.0",
+			executorImage: "tiflow:v6.5.0",
+			masterReason:  VersionSkewExceeded,
+		},
+		{
+			name:           "downgrade refused",
+			master:         "v6.5.0",
+			executor:       "v6.4.0",
+			masterImage:    "tiflow:v6.5.0",
+			executorImage:  "tiflow:v6.5.0",
+			executorReason: VersionDowngradeRefused,
+		},
+		{
+			name:          "forced downgrade",
+			master:        "v6.5.0",
+			executor:      "v6.4.0",
+			masterImage:   "tiflow:v6.5.0",
+			executorImage: "tiflow:v6.5.0",
+			forced:        true,
+		},
+		{
+			name:          "masters downgraded last",
+			master:        "v6.4.0",
+			executor:      "v6.5.0",
+			masterImage:   "tiflow:v6.5.0",
+			executorImage: "tiflow:v6.5.0",
+			forced:        true,
+			masterReason:  VersionExecutorNotDowngraded,
+		},
+		{
+			name:          "not semantic",
+			master:        "latest",
+			executor:      "nightly",
+			masterImage:   "tiflow:v6.5.0",
+			executorImage: "tiflow:v6.5.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tc := &v1alpha1.TiflowCluster{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+				Spec: v1alpha1.TiflowClusterSpec{
+					Master:   &v1alpha1.MasterSpec{ComponentSpec: v1alpha1.ComponentSpec{Version: pointer.StringPtr(test.master)}},
+					Executor: &v1alpha1.ExecutorSpec{ComponentSpec: v1alpha1.ComponentSpec{Version: pointer.StringPtr(test.executor)}},
+				},
+				Status: v1alpha1.TiflowClusterStatus{
+					Master: v1alpha1.MasterStatus{
+						Image:       test.masterImage,
+						StatefulSet: &appsv1.StatefulSetStatus{Replicas: 3, UpdatedReplicas: 3},
+					},
+					Executor: v1alpha1.ExecutorStatus{Image: test.executorImage},
+				},
+			}
+			if test.masterRolling {
+				tc.Status.Master.StatefulSet.UpdatedReplicas = 1
+			}
+			if test.forced {
+				tc.Annotations = map[string]string{label.AnnForceDowngradeKey: label.AnnForceDowngradeVal}
+			}
+
+			reason, _ := CheckVersion(tc, v1alpha1.TiFlowMasterMemberType)
+			require.Equal(t, test.masterReason, reason)
+			reason, _ = CheckVersion(tc, v1alpha1.TiFlowExecutorMemberType)
+			require.Equal(t, test.executorReason, reason)
+		})
+	}
+}
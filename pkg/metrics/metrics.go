@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+const (
+	namespace = "tiflow_operator"
+
+	// componentCluster is the component label value of the whole tiflow cluster
+	componentCluster = "cluster"
+)
+
+var (
+	// ReconcileDuration tracks the duration of reconciling each tiflow cluster
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "reconcile_duration_seconds",
+			Help:      "Bucketed histogram of the time spent reconciling a tiflow cluster.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+		}, []string{"namespace", "cluster"})
+
+	// ReconcileErrors counts the unexpected errors of reconciling each tiflow cluster
+	ReconcileErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "controller",
+			Name:      "reconcile_errors_total",
+			Help:      "Total number of errors when reconciling a tiflow cluster.",
+		}, []string{"namespace", "cluster"})
+
+	// ClusterPhase is 1 for the current phase of the tiflow cluster and its components
+	ClusterPhase = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "phase",
+			Help:      "The current phase of the tiflow cluster, tiflow-master and tiflow-executor.",
+		}, []string{"namespace", "cluster", "component", "phase"})
+
+	// ReadyReplicas tracks the ready replicas of each component
+	ReadyReplicas = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "ready_replicas",
+			Help:      "The number of ready replicas of tiflow-master and tiflow-executor.",
+		}, []string{"namespace", "cluster", "component"})
+
+	// DesiredReplicas tracks the desired replicas of each component
+	DesiredReplicas = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "desired_replicas",
+			Help:      "The number of desired replicas of tiflow-master and tiflow-executor.",
+		}, []string{"namespace", "cluster", "component"})
+
+	// MasterLeaderChanges counts the leader changes of tiflow-master observed by the operator
+	MasterLeaderChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "master_leader_changes_total",
+			Help:      "Total number of tiflow-master leader changes observed by the operator.",
+		}, []string{"namespace", "cluster"})
+
+	// PVCsPruned counts the unused PVCs of tiflow-executor deleted by the operator
+	PVCsPruned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "cluster",
+			Name:      "pvcs_pruned_total",
+			Help:      "Total number of unused tiflow-executor PVCs pruned by the operator.",
+		}, []string{"namespace", "cluster"})
+
+	// MasterAPIDuration tracks the latency of requests to tiflow-master API
+	MasterAPIDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "tiflowapi",
+			Name:      "request_duration_seconds",
+			Help:      "Bucketed histogram of the latency of requests to tiflow-master API.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"api"})
+
+	// MasterAPIErrors counts the failed requests to tiflow-master API
+	MasterAPIErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tiflowapi",
+			Name:      "request_errors_total",
+			Help:      "Total number of failed requests to tiflow-master API.",
+		}, []string{"api"})
+)
+
+var (
+	// phases remembers the last reported phase of each component, so that the stale phase series can be removed
+	phases     = map[string]string{}
+	phasesLock sync.Mutex
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcileDuration,
+		ReconcileErrors,
+		ClusterPhase,
+		ReadyReplicas,
+		DesiredReplicas,
+		MasterLeaderChanges,
+		PVCsPruned,
+		MasterAPIDuration,
+		MasterAPIErrors,
+	)
+}
+
+// ObserveMasterAPI records the latency and the result of a request to tiflow-master API
+func ObserveMasterAPI(api string, start time.Time, err error) {
+	MasterAPIDuration.WithLabelValues(api).Observe(time.Since(start).Seconds())
+	if err != nil {
+		MasterAPIErrors.WithLabelValues(api).Inc()
+	}
+}
+
+// UpdateClusterMetrics refreshes the phases and replicas of the tiflow cluster from its status
+func UpdateClusterMetrics(tc *v1alpha1.TiflowCluster) {
+	ns := tc.GetNamespace()
+	tcName := tc.GetName()
+
+	setPhase(ns, tcName, componentCluster, string(tc.Status.ClusterPhase))
+	if tc.Spec.Master != nil {
+		setPhase(ns, tcName, label.TiflowMasterLabelVal, string(tc.Status.Master.Phase))
+		ReadyReplicas.WithLabelValues(ns, tcName, label.TiflowMasterLabelVal).Set(float64(tc.MasterStsReadyReplicas()))
+		DesiredReplicas.WithLabelValues(ns, tcName, label.TiflowMasterLabelVal).Set(float64(tc.MasterStsDesiredReplicas()))
+	}
+	if tc.Spec.Executor != nil {
+		setPhase(ns, tcName, label.TiflowExecutorLabelVal, string(tc.Status.Executor.Phase))
+		ReadyReplicas.WithLabelValues(ns, tcName, label.TiflowExecutorLabelVal).Set(float64(tc.ExecutorStsReadyReplicas()))
+		DesiredReplicas.WithLabelValues(ns, tcName, label.TiflowExecutorLabelVal).Set(float64(tc.ExecutorStsDesiredReplicas()))
+	}
+}
+
+// DeleteClusterMetrics removes all series of the tiflow cluster once it's deleted
+func DeleteClusterMetrics(ns, tcName string) {
+	ReconcileDuration.DeleteLabelValues(ns, tcName)
+	ReconcileErrors.DeleteLabelValues(ns, tcName)
+	MasterLeaderChanges.DeleteLabelValues(ns, tcName)
+	PVCsPruned.DeleteLabelValues(ns, tcName)
+
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+	for _, component := range []string{componentCluster, label.TiflowMasterLabelVal, label.TiflowExecutorLabelVal} {
+		key := phaseKey(ns, tcName, component)
+		if last, ok := phases[key]; ok {
+			ClusterPhase.DeleteLabelValues(ns, tcName, component, last)
+			delete(phases, key)
+		}
+		ReadyReplicas.DeleteLabelValues(ns, tcName, component)
+		DesiredReplicas.DeleteLabelValues(ns, tcName, component)
+	}
+}
+
+func setPhase(ns, tcName, component, phase string) {
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+
+	key := phaseKey(ns, tcName, component)
+	if last, ok := phases[key]; ok && last != phase {
+		ClusterPhase.DeleteLabelValues(ns, tcName, component, last)
+	}
+	phases[key] = phase
+	ClusterPhase.WithLabelValues(ns, tcName, component, phase).Set(1)
+}
+
+func phaseKey(ns, tcName, component string) string {
+	return ns + "/" + tcName + "/" + component
+}
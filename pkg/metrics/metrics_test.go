@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestUpdateClusterMetrics(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{}
+	tc.Namespace = "ns"
+	tc.Name = "demo"
+	tc.Spec.Master = &v1alpha1.MasterSpec{Replicas: 3}
+	tc.Status.ClusterPhase = v1alpha1.ClusterStarting
+	tc.Status.Master.Phase = v1alpha1.MasterCreating
+
+	UpdateClusterMetrics(tc)
+	require.Equal(t, float64(1), testutil.ToFloat64(ClusterPhase.WithLabelValues("ns", "demo", componentCluster, string(v1alpha1.ClusterStarting))))
+	require.Equal(t, float64(3), testutil.ToFloat64(DesiredReplicas.WithLabelValues("ns", "demo", label.TiflowMasterLabelVal)))
+
+	// the series of stale phase is removed
+	tc.Status.Master.Phase = v1alpha1.MasterRunning
+	UpdateClusterMetrics(tc)
+	require.Equal(t, 2, testutil.CollectAndCount(ClusterPhase))
+
+	DeleteClusterMetrics("ns", "demo")
+	require.Equal(t, 0, testutil.CollectAndCount(ClusterPhase))
+	require.Equal(t, 0, testutil.CollectAndCount(DesiredReplicas))
+}
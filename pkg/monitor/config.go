@@ -0,0 +1,201 @@
+package monitor
+
+import (
+	_ "embed"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
+const (
+	prometheusPort = 9090
+	grafanaPort    = 3000
+
+	masterPort   = 10240
+	executorPort = 10241
+
+	// keys of the monitor ConfigMap
+	prometheusConfigKey   = "prometheus.yml"
+	grafanaDatasourceKey  = "datasource.yaml"
+	grafanaDashboardsKey  = "dashboards.yaml"
+	grafanaTiflowBoardKey = "tiflow.json"
+
+	prometheusConfigPath = "/etc/prometheus"
+	grafanaProvisionPath = "/etc/grafana/provisioning"
+	grafanaDashboardPath = grafanaProvisionPath + "/dashboards/json"
+	dataPath             = "/data"
+	clientCertPath       = "/var/lib/tiflow-client-certs"
+)
+
+//go:embed dashboards/tiflow.json
+var tiflowDashboard string
+
+// scrapeTarget is a tiflow component of a TiflowCluster scraped by Prometheus
+type scrapeTarget struct {
+	cluster   pingcapcomv1alpha1.TiflowClusterRef
+	component string
+	// host resolves to the addresses of all Pods of the component
+	host       string
+	port       int32
+	tlsEnabled bool
+	// serverName is used to verify the certificate of the component
+	serverName string
+}
+
+// clusterTargets returns the tiflow components of tc scraped by Prometheus
+func clusterTargets(tc *pingcapcomv1alpha1.TiflowCluster) []scrapeTarget {
+	ref := pingcapcomv1alpha1.TiflowClusterRef{Namespace: tc.GetNamespace(), Name: tc.GetName()}
+	var targets []scrapeTarget
+	if !tc.WithoutLocalMaster() {
+		targets = append(targets, scrapeTarget{
+			cluster:    ref,
+			component:  label.TiflowMasterLabelVal,
+			host:       fmt.Sprintf("%s.%s.svc", controller.TiflowMasterPeerMemberName(tc.Name), tc.Namespace),
+			port:       masterPort,
+			tlsEnabled: tc.IsClusterTLSEnabled(),
+			serverName: fmt.Sprintf("%s.%s.svc", controller.TiflowMasterMemberName(tc.Name), tc.Namespace),
+		})
+	}
+	if !tc.WithoutLocalExecutor() {
+		host := fmt.Sprintf("%s.%s.svc", controller.TiflowExecutorPeerMemberName(tc.Name), tc.Namespace)
+		targets = append(targets, scrapeTarget{
+			cluster:    ref,
+			component:  label.TiflowExecutorLabelVal,
+			host:       host,
+			port:       executorPort,
+			tlsEnabled: tc.IsClusterTLSEnabled(),
+			serverName: host,
+		})
+	}
+	return targets
+}
+
+// clientCertDir returns the directory where the client certificates of the cluster are mounted
+func clientCertDir(ref pingcapcomv1alpha1.TiflowClusterRef) string {
+	return path.Join(clientCertPath, ref.Namespace, util.ClusterClientTLSSecretName(ref.Name))
+}
+
+// clientTLSSecretName returns the name of the secret in the namespace of tm which holds the client certificates
+// of the cluster, the namespace of the cluster is prefixed if it's not the one of tm, so that the clusters of
+// the same name in different namespaces don't share the certificates.
+func clientTLSSecretName(tm *pingcapcomv1alpha1.TiflowMonitor, ref pingcapcomv1alpha1.TiflowClusterRef) string {
+	if ref.Namespace == tm.Namespace {
+		return util.ClusterClientTLSSecretName(ref.Name)
+	}
+	return util.ClusterClientTLSSecretName(fmt.Sprintf("%s-%s", ref.Namespace, ref.Name))
+}
+
+type prometheusConfig struct {
+	Global        globalConfig   `json:"global"`
+	ScrapeConfigs []scrapeConfig `json:"scrape_configs"`
+}
+
+type globalConfig struct {
+	ScrapeInterval     string `json:"scrape_interval,omitempty"`
+	EvaluationInterval string `json:"evaluation_interval,omitempty"`
+}
+
+type scrapeConfig struct {
+	JobName        string          `json:"job_name"`
+	Scheme         string          `json:"scheme"`
+	MetricsPath    string          `json:"metrics_path"`
+	TLSConfig      *tlsConfig      `json:"tls_config,omitempty"`
+	DNSSDConfigs   []dnsSDConfig   `json:"dns_sd_configs"`
+	RelabelConfigs []relabelConfig `json:"relabel_configs"`
+}
+
+type tlsConfig struct {
+	CAFile     string `json:"ca_file"`
+	CertFile   string `json:"cert_file"`
+	KeyFile    string `json:"key_file"`
+	ServerName string `json:"server_name"`
+}
+
+type dnsSDConfig struct {
+	Names []string `json:"names"`
+	Type  string   `json:"type"`
+	Port  int32    `json:"port"`
+}
+
+type relabelConfig struct {
+	TargetLabel string `json:"target_label"`
+	Replacement string `json:"replacement"`
+}
+
+// getPrometheusConfig renders the Prometheus configuration, the Pods of each target are discovered
+// through the A records of its headless service, so no extra RBAC is required by Prometheus.
+func getPrometheusConfig(tm *pingcapcomv1alpha1.TiflowMonitor, targets []scrapeTarget) (string, error) {
+	cfg := prometheusConfig{
+		Global: globalConfig{
+			ScrapeInterval:     tm.Spec.Prometheus.ScrapeInterval,
+			EvaluationInterval: tm.Spec.Prometheus.ScrapeInterval,
+		},
+		ScrapeConfigs: []scrapeConfig{},
+	}
+
+	for _, target := range targets {
+		sc := scrapeConfig{
+			JobName:     fmt.Sprintf("%s/%s/%s", target.cluster.Namespace, target.cluster.Name, target.component),
+			Scheme:      "http",
+			MetricsPath: "/metrics",
+			DNSSDConfigs: []dnsSDConfig{
+				{Names: []string{target.host}, Type: "A", Port: target.port},
+			},
+			RelabelConfigs: []relabelConfig{
+				{TargetLabel: "cluster", Replacement: target.cluster.Name},
+				{TargetLabel: "namespace", Replacement: target.cluster.Namespace},
+				{TargetLabel: "component", Replacement: target.component},
+			},
+		}
+		if target.tlsEnabled {
+			certDir := clientCertDir(target.cluster)
+			sc.Scheme = "https"
+			sc.TLSConfig = &tlsConfig{
+				CAFile:     path.Join(certDir, corev1.ServiceAccountRootCAKey),
+				CertFile:   path.Join(certDir, corev1.TLSCertKey),
+				KeyFile:    path.Join(certDir, corev1.TLSPrivateKeyKey),
+				ServerName: target.serverName,
+			}
+		}
+		cfg.ScrapeConfigs = append(cfg.ScrapeConfigs, sc)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getGrafanaDatasource renders the datasource provisioning of Grafana,
+// Prometheus is reached by localhost as they are in the same Pod.
+func getGrafanaDatasource() string {
+	return fmt.Sprintf(`apiVersion: 1
+datasources:
+- name: tiflow
+  type: prometheus
+  access: proxy
+  url: http://127.0.0.1:%d
+  isDefault: true
+`, prometheusPort)
+}
+
+// getGrafanaDashboards renders the dashboard provisioning of Grafana
+func getGrafanaDashboards() string {
+	return fmt.Sprintf(`apiVersion: 1
+providers:
+- name: tiflow
+  folder: Tiflow
+  type: file
+  disableDeletion: true
+  options:
+    path: %s
+`, grafanaDashboardPath)
+}
@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/yaml"
+
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func newTiflowCluster(ns, name string, tls bool) *pingcapcomv1alpha1.TiflowCluster {
+	return &pingcapcomv1alpha1.TiflowCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: pingcapcomv1alpha1.TiflowClusterSpec{
+			Master:     &pingcapcomv1alpha1.MasterSpec{},
+			Executor:   &pingcapcomv1alpha1.ExecutorSpec{},
+			TLSCluster: pointer.BoolPtr(tls),
+		},
+	}
+}
+
+func TestPrometheusConfig(t *testing.T) {
+	tm := &pingcapcomv1alpha1.TiflowMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitor", Name: "demo"},
+	}
+	tm.Spec.Prometheus.ScrapeInterval = "30s"
+
+	executorOnly := newTiflowCluster("ns-2", "executors", false)
+	executorOnly.Spec.Master = nil
+	targets := append(clusterTargets(newTiflowCluster("ns-1", "basic", true)), clusterTargets(executorOnly)...)
+	require.Len(t, targets, 3)
+
+	data, err := getPrometheusConfig(tm, targets)
+	require.NoError(t, err)
+
+	cfg := prometheusConfig{}
+	require.NoError(t, yaml.Unmarshal([]byte(data), &cfg))
+	require.Equal(t, "30s", cfg.Global.ScrapeInterval)
+	require.Len(t, cfg.ScrapeConfigs, 3)
+
+	master := cfg.ScrapeConfigs[0]
+	require.Equal(t, "ns-1/basic/tiflow-master", master.JobName)
+	require.Equal(t, "https", master.Scheme)
+	require.Equal(t, []string{"basic-tiflow-master-peer.ns-1.svc"}, master.DNSSDConfigs[0].Names)
+	require.Equal(t, int32(masterPort), master.DNSSDConfigs[0].Port)
+	require.Equal(t, "/var/lib/tiflow-client-certs/ns-1/basic-cluster-client-secret/ca.crt", master.TLSConfig.CAFile)
+	require.Equal(t, "basic-tiflow-master.ns-1.svc", master.TLSConfig.ServerName)
+
+	executor := cfg.ScrapeConfigs[2]
+	require.Equal(t, "ns-2/executors/tiflow-executor", executor.JobName)
+	require.Equal(t, "http", executor.Scheme)
+	require.Nil(t, executor.TLSConfig)
+	require.Contains(t, executor.RelabelConfigs, relabelConfig{TargetLabel: "cluster", Replacement: "executors"})
+}
+
+func TestMonitorDeployment(t *testing.T) {
+	tm := &pingcapcomv1alpha1.TiflowMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo"},
+		Spec: pingcapcomv1alpha1.TiflowMonitorSpec{
+			Grafana: &pingcapcomv1alpha1.GrafanaSpec{},
+		},
+	}
+	targets := clusterTargets(newTiflowCluster("ns-1", "basic", true))
+	cm, err := getMonitorConfigMap(tm, targets)
+	require.NoError(t, err)
+	require.Contains(t, cm.Data, grafanaTiflowBoardKey)
+
+	deploy := getMonitorDeployment(tm, targets, cm)
+	podSpec := deploy.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 2)
+	require.Equal(t, "prom/prometheus:v2.37.0", podSpec.Containers[0].Image)
+	require.Equal(t, "grafana/grafana:9.1.0", podSpec.Containers[1].Image)
+	require.NotNil(t, podSpec.Volumes[0].EmptyDir)
+
+	// the client secret is mounted once for both components of the cluster
+	var secrets []string
+	for _, vol := range podSpec.Volumes {
+		if vol.Secret != nil {
+			secrets = append(secrets, vol.Secret.SecretName)
+		}
+	}
+	require.Equal(t, []string{"basic-cluster-client-secret"}, secrets)
+
+	// config changes roll the monitor Pod
+	tm.Spec.Prometheus.ScrapeInterval = "1m"
+	newCm, err := getMonitorConfigMap(tm, targets)
+	require.NoError(t, err)
+	newDeploy := getMonitorDeployment(tm, targets, newCm)
+	require.NotEqual(t, deploy.Spec.Template.Annotations[annConfigDigest], newDeploy.Spec.Template.Annotations[annConfigDigest])
+}
@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"context"
+
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ControlInterface implements the control logic for updating TiflowMonitor and its children Deployment and Services.
+// It is implemented as an interface to allow for extensions that provide different semantics.
+// Currently, there is only one implementation.
+type ControlInterface interface {
+	// UpdateTiflowMonitor implements the control logic for Prometheus and Grafana of TiflowMonitor
+	UpdateTiflowMonitor(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) error
+}
+
+type defaultTiflowMonitorControl struct {
+	cli            client.Client
+	monitorManager MonitorManager
+}
+
+// NewDefaultTiflowMonitorControl returns a new instance of the default implementation ControlInterface that
+// implements the documented semantics for TiflowMonitor.
+func NewDefaultTiflowMonitorControl(cli client.Client) ControlInterface {
+	return &defaultTiflowMonitorControl{
+		cli:            cli,
+		monitorManager: NewMonitorManager(cli),
+	}
+}
+
+// UpdateTiflowMonitor executes the core logic loop for a TiflowMonitor.
+func (c *defaultTiflowMonitorControl) UpdateTiflowMonitor(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) error {
+	return c.monitorManager.Sync(ctx, tm)
+}
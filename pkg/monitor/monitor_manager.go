@@ -0,0 +1,558 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+	"github.com/pingcap/tiflow-operator/pkg/controller"
+	mngerutils "github.com/pingcap/tiflow-operator/pkg/manager/utils"
+	"github.com/pingcap/tiflow-operator/pkg/util"
+)
+
+const (
+	// annConfigDigest is the digest of the monitor ConfigMap, the monitor Pod is recreated once it changes
+	annConfigDigest = "tiflow.pingcap.com/config-digest"
+
+	defaultStorage = "10Gi"
+	// monitorFSGroup is the group of nobody, which Prometheus runs as
+	monitorFSGroup = 65534
+)
+
+// MonitorManager implements the logic for syncing TiflowMonitor
+type MonitorManager interface {
+	// Sync syncs the Prometheus and Grafana of the given TiflowMonitor and refreshes its status
+	Sync(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) error
+}
+
+type monitorManager struct {
+	cli client.Client
+}
+
+// NewMonitorManager returns a MonitorManager which deploys Prometheus and Grafana in one Pod
+func NewMonitorManager(cli client.Client) MonitorManager {
+	return &monitorManager{
+		cli: cli,
+	}
+}
+
+func (m *monitorManager) Sync(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) error {
+	targets, scraped, missing, err := m.resolveTargets(ctx, tm)
+	if err != nil {
+		return err
+	}
+
+	cm, err := getMonitorConfigMap(tm, targets)
+	if err != nil {
+		return err
+	}
+	if err = m.syncConfigMap(ctx, cm); err != nil {
+		return err
+	}
+
+	if tm.Spec.Persistent {
+		if err = m.syncPVC(ctx, tm); err != nil {
+			return err
+		}
+	}
+
+	deploy, err := m.syncDeployment(ctx, getMonitorDeployment(tm, targets, cm))
+	if err != nil {
+		return err
+	}
+
+	promSvc, err := m.syncService(ctx, getPrometheusService(tm))
+	if err != nil {
+		return err
+	}
+
+	tm.Status.GrafanaURL = ""
+	if tm.GrafanaEnabled() {
+		grafanaSvc, err := m.syncService(ctx, getGrafanaService(tm))
+		if err != nil {
+			return err
+		}
+		tm.Status.GrafanaURL = serviceURL(grafanaSvc, grafanaPort)
+	} else if err = m.deleteService(ctx, tm.Namespace, controller.TiflowMonitorGrafanaName(tm.Name)); err != nil {
+		return err
+	}
+
+	tm.Status.PrometheusURL = serviceURL(promSvc, prometheusPort)
+	tm.Status.Ready = deploy.Status.AvailableReplicas > 0
+	tm.Status.Clusters = scraped
+	tm.Status.Message = ""
+	if len(missing) > 0 {
+		tm.Status.Message = fmt.Sprintf("TiflowClusters %s are not found", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// resolveTargets returns the scrape targets of the referenced TiflowClusters,
+// the clusters which are not found are skipped until they are created.
+func (m *monitorManager) resolveTargets(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) ([]scrapeTarget, []string, []string, error) {
+	var targets []scrapeTarget
+	var scraped, missing []string
+	for _, ref := range tm.ClusterRefs() {
+		tc := &pingcapcomv1alpha1.TiflowCluster{}
+		err := m.cli.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, tc)
+		if errors.IsNotFound(err) {
+			missing = append(missing, ref.String())
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("resolveTargets: failed to get TiflowCluster %s, error: %v", ref, err)
+		}
+		targets = append(targets, clusterTargets(tc)...)
+		scraped = append(scraped, ref.String())
+	}
+	return targets, scraped, missing, nil
+}
+
+func (m *monitorManager) syncConfigMap(ctx context.Context, cm *corev1.ConfigMap) error {
+	oldCm := &corev1.ConfigMap{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: cm.Namespace, Name: cm.Name}, oldCm)
+	if errors.IsNotFound(err) {
+		return m.cli.Create(ctx, cm)
+	}
+	if err != nil {
+		return fmt.Errorf("syncConfigMap: failed to get configmap %s/%s, error: %v", cm.Namespace, cm.Name, err)
+	}
+
+	if equality.Semantic.DeepEqual(oldCm.Data, cm.Data) && equality.Semantic.DeepEqual(oldCm.Labels, cm.Labels) {
+		return nil
+	}
+	newCm := oldCm.DeepCopy()
+	newCm.Labels = cm.Labels
+	newCm.Data = cm.Data
+	return m.cli.Update(ctx, newCm)
+}
+
+// syncPVC creates the PVC of monitor data, the PVC is never shrunk or removed by the operator
+// except by the garbage collector after TiflowMonitor is deleted.
+func (m *monitorManager) syncPVC(ctx context.Context, tm *pingcapcomv1alpha1.TiflowMonitor) error {
+	pvc, err := getMonitorPVC(tm)
+	if err != nil {
+		return err
+	}
+	err = m.cli.Get(ctx, types.NamespacedName{Namespace: pvc.Namespace, Name: pvc.Name}, &corev1.PersistentVolumeClaim{})
+	if errors.IsNotFound(err) {
+		klog.Infof("tiflow monitor: [%s/%s] creating pvc %s", tm.Namespace, tm.Name, pvc.Name)
+		return m.cli.Create(ctx, pvc)
+	}
+	if err != nil {
+		return fmt.Errorf("syncPVC: failed to get pvc %s/%s, error: %v", pvc.Namespace, pvc.Name, err)
+	}
+	return nil
+}
+
+func (m *monitorManager) syncDeployment(ctx context.Context, newDeploy *apps.Deployment) (*apps.Deployment, error) {
+	oldDeploy := &apps.Deployment{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: newDeploy.Namespace, Name: newDeploy.Name}, oldDeploy)
+	if errors.IsNotFound(err) {
+		if err = mngerutils.SetDeploymentLastAppliedConfigAnnotation(newDeploy); err != nil {
+			return nil, err
+		}
+		klog.Infof("tiflow monitor: [%s/%s] creating deployment", newDeploy.Namespace, newDeploy.Name)
+		return newDeploy, m.cli.Create(ctx, newDeploy)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("syncDeployment: failed to get deployment %s/%s, error: %v", newDeploy.Namespace, newDeploy.Name, err)
+	}
+
+	if mngerutils.DeploymentEqual(newDeploy, oldDeploy) {
+		return oldDeploy, nil
+	}
+
+	if err = mngerutils.SetDeploymentLastAppliedConfigAnnotation(newDeploy); err != nil {
+		return nil, err
+	}
+	deploy := oldDeploy.DeepCopy()
+	deploy.Labels = newDeploy.Labels
+	if deploy.Annotations == nil {
+		deploy.Annotations = map[string]string{}
+	}
+	deploy.Annotations[mngerutils.LastAppliedConfigAnnotation] = newDeploy.Annotations[mngerutils.LastAppliedConfigAnnotation]
+	deploy.Spec.Replicas = newDeploy.Spec.Replicas
+	deploy.Spec.Strategy = newDeploy.Spec.Strategy
+	deploy.Spec.Template = newDeploy.Spec.Template
+	return deploy, m.cli.Update(ctx, deploy)
+}
+
+func (m *monitorManager) syncService(ctx context.Context, newSvc *corev1.Service) (*corev1.Service, error) {
+	oldSvc := &corev1.Service{}
+	err := m.cli.Get(ctx, types.NamespacedName{Namespace: newSvc.Namespace, Name: newSvc.Name}, oldSvc)
+	if errors.IsNotFound(err) {
+		if err = controller.SetServiceLastAppliedConfigAnnotation(newSvc); err != nil {
+			return nil, err
+		}
+		return newSvc, m.cli.Create(ctx, newSvc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("syncService: failed to get svc %s/%s, error: %v", newSvc.Namespace, newSvc.Name, err)
+	}
+
+	equal, err := controller.ServiceEqual(newSvc, oldSvc)
+	if err != nil {
+		return nil, err
+	}
+	if equal {
+		return oldSvc, nil
+	}
+
+	svc := oldSvc.DeepCopy()
+	svc.Spec = newSvc.Spec
+	svc.Labels = newSvc.Labels
+	svc.Annotations = util.CombineStringMap(svc.Annotations, newSvc.Annotations)
+	if err = controller.SetServiceLastAppliedConfigAnnotation(svc); err != nil {
+		return nil, err
+	}
+	// keep the cluster ip allocated by kubernetes
+	svc.Spec.ClusterIP = oldSvc.Spec.ClusterIP
+	svc.Spec.ClusterIPs = oldSvc.Spec.ClusterIPs
+	return svc, m.cli.Update(ctx, svc)
+}
+
+func (m *monitorManager) deleteService(ctx context.Context, ns, name string) error {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	if err := m.cli.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("deleteService: failed to delete svc %s/%s, error: %v", ns, name, err)
+	}
+	return nil
+}
+
+// serviceURL returns the address to access the service, the ingress of LoadBalancer is preferred
+func serviceURL(svc *corev1.Service, port int32) string {
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			host := ingress.Hostname
+			if host == "" {
+				host = ingress.IP
+			}
+			if host != "" {
+				return fmt.Sprintf("http://%s:%d", host, port)
+			}
+		}
+	}
+	return fmt.Sprintf("http://%s.%s.svc:%d", svc.Name, svc.Namespace, port)
+}
+
+func getMonitorConfigMap(tm *pingcapcomv1alpha1.TiflowMonitor, targets []scrapeTarget) (*corev1.ConfigMap, error) {
+	promConfig, err := getPrometheusConfig(tm, targets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render prometheus config of tiflow monitor %s/%s, error: %v", tm.Namespace, tm.Name, err)
+	}
+
+	data := map[string]string{
+		prometheusConfigKey: promConfig,
+	}
+	if tm.GrafanaEnabled() {
+		data[grafanaDatasourceKey] = getGrafanaDatasource()
+		data[grafanaDashboardsKey] = getGrafanaDashboards()
+		data[grafanaTiflowBoardKey] = tiflowDashboard
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiflowMonitorMemberName(tm.Name),
+			Namespace:       tm.Namespace,
+			Labels:          label.NewMonitor().Instance(tm.Name).Monitor().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetMonitorOwnerRef(tm)},
+		},
+		Data: data,
+	}, nil
+}
+
+func getMonitorPVC(tm *pingcapcomv1alpha1.TiflowMonitor) (*corev1.PersistentVolumeClaim, error) {
+	storage := tm.Spec.Storage
+	if storage == "" {
+		storage = defaultStorage
+	}
+	quantity, err := resource.ParseQuantity(storage)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse storage request for tiflow monitor %s/%s, error: %v", tm.Namespace, tm.Name, err)
+	}
+
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiflowMonitorMemberName(tm.Name),
+			Namespace:       tm.Namespace,
+			Labels:          label.NewMonitor().Instance(tm.Name).Monitor().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetMonitorOwnerRef(tm)},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: tm.Spec.StorageClassName,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: quantity,
+				},
+			},
+		},
+	}, nil
+}
+
+func getMonitorDeployment(tm *pingcapcomv1alpha1.TiflowMonitor, targets []scrapeTarget, cm *corev1.ConfigMap) *apps.Deployment {
+	monitorLabels := label.NewMonitor().Instance(tm.Name).Monitor()
+	digest, _ := mngerutils.Sha256Sum(cm.Data)
+
+	dataVolume := corev1.Volume{Name: "data"}
+	if tm.Spec.Persistent {
+		dataVolume.VolumeSource = corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: controller.TiflowMonitorMemberName(tm.Name),
+			},
+		}
+	} else {
+		dataVolume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	}
+	vols := []corev1.Volume{
+		dataVolume,
+		{Name: "prometheus-config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+					Items:                []corev1.KeyToPath{{Key: prometheusConfigKey, Path: prometheusConfigKey}},
+				},
+			},
+		},
+	}
+
+	promMounts := []corev1.VolumeMount{
+		{Name: "data", MountPath: dataPath + "/prometheus", SubPath: "prometheus"},
+		{Name: "prometheus-config", ReadOnly: true, MountPath: prometheusConfigPath},
+	}
+
+	// mount client certificates of each TLS enabled cluster, the secret is optional to
+	// keep the monitor running when the certificates of some clusters are not copied yet
+	var tlsClusters []pingcapcomv1alpha1.TiflowClusterRef
+	seen := map[string]bool{}
+	for _, target := range targets {
+		if target.tlsEnabled && !seen[target.cluster.String()] {
+			seen[target.cluster.String()] = true
+			tlsClusters = append(tlsClusters, target.cluster)
+		}
+	}
+	sort.Slice(tlsClusters, func(i, j int) bool {
+		return tlsClusters[i].String() < tlsClusters[j].String()
+	})
+	for i, ref := range tlsClusters {
+		volName := fmt.Sprintf("tiflow-client-tls-%d", i)
+		vols = append(vols, corev1.Volume{
+			Name: volName, VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: clientTLSSecretName(tm, ref),
+					Optional:   pointer.BoolPtr(true),
+				},
+			},
+		})
+		promMounts = append(promMounts, corev1.VolumeMount{
+			Name: volName, ReadOnly: true, MountPath: clientCertDir(ref),
+		})
+	}
+
+	promArgs := []string{
+		fmt.Sprintf("--config.file=%s/%s", prometheusConfigPath, prometheusConfigKey),
+		fmt.Sprintf("--storage.tsdb.path=%s/prometheus", dataPath),
+		fmt.Sprintf("--web.listen-address=:%d", prometheusPort),
+	}
+	if tm.Spec.Prometheus.RetentionTime != "" {
+		promArgs = append(promArgs, fmt.Sprintf("--storage.tsdb.retention.time=%s", tm.Spec.Prometheus.RetentionTime))
+	}
+
+	prometheus := corev1.Container{
+		Name:            "prometheus",
+		Image:           tm.PrometheusImage(),
+		ImagePullPolicy: tm.PrometheusImagePullPolicy(),
+		Args:            promArgs,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "prometheus",
+				ContainerPort: prometheusPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/-/ready",
+					Port: intstr.FromInt(prometheusPort),
+				},
+			},
+		},
+		Resources:    controller.ContainerResource(tm.Spec.Prometheus.ResourceRequirements),
+		VolumeMounts: promMounts,
+	}
+	containers := []corev1.Container{prometheus}
+
+	if tm.GrafanaEnabled() {
+		vols = append(vols, corev1.Volume{
+			Name: "grafana-provisioning",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+					Items: []corev1.KeyToPath{
+						{Key: grafanaDatasourceKey, Path: "datasources/" + grafanaDatasourceKey},
+						{Key: grafanaDashboardsKey, Path: "dashboards/" + grafanaDashboardsKey},
+						{Key: grafanaTiflowBoardKey, Path: "dashboards/json/" + grafanaTiflowBoardKey},
+					},
+				},
+			},
+		})
+		containers = append(containers, getGrafanaContainer(tm))
+	}
+
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            controller.TiflowMonitorMemberName(tm.Name),
+			Namespace:       tm.Namespace,
+			Labels:          monitorLabels.Copy().Labels(),
+			OwnerReferences: []metav1.OwnerReference{controller.GetMonitorOwnerRef(tm)},
+		},
+		Spec: apps.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: monitorLabels.LabelSelector(),
+			// the data volume can't be shared by two Pods
+			Strategy: apps.DeploymentStrategy{Type: apps.RecreateDeploymentStrategyType},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      monitorLabels.Copy().Labels(),
+					Annotations: map[string]string{annConfigDigest: digest},
+				},
+				Spec: corev1.PodSpec{
+					Containers:       containers,
+					Volumes:          vols,
+					ImagePullSecrets: tm.Spec.ImagePullSecrets,
+					NodeSelector:     tm.Spec.NodeSelector,
+					Tolerations:      tm.Spec.Tolerations,
+					SecurityContext: &corev1.PodSecurityContext{
+						FSGroup: pointer.Int64Ptr(monitorFSGroup),
+					},
+				},
+			},
+		},
+	}
+}
+
+func getGrafanaContainer(tm *pingcapcomv1alpha1.TiflowMonitor) corev1.Container {
+	env := []corev1.EnvVar{
+		{Name: "GF_PATHS_DATA", Value: dataPath + "/grafana"},
+		{Name: "GF_PATHS_PROVISIONING", Value: grafanaProvisionPath},
+	}
+	if secretName := tm.Spec.Grafana.AdminSecretName; secretName != "" {
+		env = append(env,
+			corev1.EnvVar{Name: "GF_SECURITY_ADMIN_USER", ValueFrom: secretKeyRef(secretName, "username")},
+			corev1.EnvVar{Name: "GF_SECURITY_ADMIN_PASSWORD", ValueFrom: secretKeyRef(secretName, "password")},
+		)
+	}
+
+	return corev1.Container{
+		Name:            "grafana",
+		Image:           tm.GrafanaImage(),
+		ImagePullPolicy: tm.GrafanaImagePullPolicy(),
+		Env:             env,
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "grafana",
+				ContainerPort: grafanaPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		ReadinessProbe: &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/api/health",
+					Port: intstr.FromInt(grafanaPort),
+				},
+			},
+		},
+		Resources: controller.ContainerResource(tm.Spec.Grafana.ResourceRequirements),
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: dataPath + "/grafana", SubPath: "grafana"},
+			{Name: "grafana-provisioning", ReadOnly: true, MountPath: grafanaProvisionPath},
+		},
+	}
+}
+
+func secretKeyRef(secretName, key string) *corev1.EnvVarSource {
+	return &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}
+}
+
+func getPrometheusService(tm *pingcapcomv1alpha1.TiflowMonitor) *corev1.Service {
+	return getMonitorService(tm, controller.TiflowMonitorPrometheusName(tm.Name), "http-prometheus",
+		prometheusPort, &tm.Spec.Prometheus.Service)
+}
+
+func getGrafanaService(tm *pingcapcomv1alpha1.TiflowMonitor) *corev1.Service {
+	return getMonitorService(tm, controller.TiflowMonitorGrafanaName(tm.Name), "http-grafana",
+		grafanaPort, &tm.Spec.Grafana.Service)
+}
+
+func getMonitorService(tm *pingcapcomv1alpha1.TiflowMonitor, name, portName string, port int32, svcSpec *pingcapcomv1alpha1.ServiceSpec) *corev1.Service {
+	monitorLabels := label.NewMonitor().Instance(tm.Name).Monitor()
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       tm.Namespace,
+			Labels:          util.CombineStringMap(monitorLabels.Copy().Labels(), svcSpec.Labels),
+			Annotations:     util.CopyStringMap(svcSpec.Annotations),
+			OwnerReferences: []metav1.OwnerReference{controller.GetMonitorOwnerRef(tm)},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       portName,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+			Selector: monitorLabels.Labels(),
+		},
+	}
+
+	if svcSpec.Type != "" {
+		svc.Spec.Type = svcSpec.Type
+	}
+	if svcSpec.NodePort != nil {
+		svc.Spec.Ports[0].NodePort = *svcSpec.NodePort
+	}
+	if svcSpec.Type == corev1.ServiceTypeLoadBalancer {
+		if svcSpec.LoadBalancerIP != nil {
+			svc.Spec.LoadBalancerIP = *svcSpec.LoadBalancerIP
+		}
+		if svcSpec.LoadBalancerSourceRanges != nil {
+			svc.Spec.LoadBalancerSourceRanges = svcSpec.LoadBalancerSourceRanges
+		}
+	}
+	if svcSpec.ExternalTrafficPolicy != nil {
+		svc.Spec.ExternalTrafficPolicy = *svcSpec.ExternalTrafficPolicy
+	}
+	if svcSpec.ClusterIP != nil {
+		svc.Spec.ClusterIP = *svcSpec.ClusterIP
+	}
+	if svcSpec.PortName != nil {
+		svc.Spec.Ports[0].Name = *svcSpec.PortName
+	}
+	return svc
+}
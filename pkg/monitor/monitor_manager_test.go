@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	pingcapcomv1alpha1 "github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestMonitorSync(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, pingcapcomv1alpha1.AddToScheme(scheme))
+
+	local := newTiflowCluster("monitor", "basic", true)
+	remote := newTiflowCluster("ns-1", "basic", true)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(local, remote).Build()
+	m := NewMonitorManager(cli)
+
+	tm := &pingcapcomv1alpha1.TiflowMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitor", Name: "demo"},
+		Spec: pingcapcomv1alpha1.TiflowMonitorSpec{
+			Clusters: []pingcapcomv1alpha1.TiflowClusterRef{
+				{Name: "basic"},
+				{Namespace: "ns-1", Name: "basic"},
+				{Namespace: "ns-2", Name: "absent"},
+			},
+			Grafana: &pingcapcomv1alpha1.GrafanaSpec{},
+		},
+	}
+	require.NoError(t, m.Sync(context.TODO(), tm))
+
+	// the clusters which are not found are reported in the status
+	require.Equal(t, []string{"monitor/basic", "ns-1/basic"}, tm.Status.Clusters)
+	require.Equal(t, "TiflowClusters ns-2/absent are not found", tm.Status.Message)
+	require.Equal(t, "http://demo-prometheus.monitor.svc:9090", tm.Status.PrometheusURL)
+	require.Equal(t, "http://demo-grafana.monitor.svc:3000", tm.Status.GrafanaURL)
+	require.False(t, tm.Status.Ready)
+
+	// the clusters of the same name in different namespaces are mounted with their own client secrets
+	deploy := &apps.Deployment{}
+	require.NoError(t, cli.Get(context.TODO(), types.NamespacedName{Namespace: "monitor", Name: "demo-monitor"}, deploy))
+	var secrets []string
+	for _, vol := range deploy.Spec.Template.Spec.Volumes {
+		if vol.Secret != nil {
+			secrets = append(secrets, vol.Secret.SecretName)
+		}
+	}
+	require.Equal(t, []string{"basic-cluster-client-secret", "ns-1-basic-cluster-client-secret"}, secrets)
+	require.Len(t, deploy.Spec.Template.Spec.Containers, 2)
+
+	grafanaKey := types.NamespacedName{Namespace: "monitor", Name: "demo-grafana"}
+	require.NoError(t, cli.Get(context.TODO(), grafanaKey, &corev1.Service{}))
+
+	// the missing cluster is scraped once it's created
+	require.NoError(t, cli.Create(context.TODO(), newTiflowCluster("ns-2", "absent", false)))
+	require.NoError(t, m.Sync(context.TODO(), tm))
+	require.Equal(t, []string{"monitor/basic", "ns-1/basic", "ns-2/absent"}, tm.Status.Clusters)
+	require.Empty(t, tm.Status.Message)
+
+	// disabling Grafana removes its container and service
+	tm.Spec.Grafana = nil
+	require.NoError(t, m.Sync(context.TODO(), tm))
+	require.Empty(t, tm.Status.GrafanaURL)
+	require.NoError(t, cli.Get(context.TODO(), types.NamespacedName{Namespace: "monitor", Name: "demo-monitor"}, deploy))
+	require.Len(t, deploy.Spec.Template.Spec.Containers, 1)
+	err := cli.Get(context.TODO(), grafanaKey, &corev1.Service{})
+	require.True(t, errors.IsNotFound(err))
+}
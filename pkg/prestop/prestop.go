@@ -0,0 +1,137 @@
+package prestop
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+// pollInterval is the interval to check whether the tiflow-master has handled the request
+var pollInterval = time.Second
+
+// ResignMaster resigns the leadership of tiflow-master podName if it's the leader,
+// and waits until another tiflow-master takes over before the deadline.
+func ResignMaster(masterClient tiflowapi.MasterClient, podName string, deadline time.Time) error {
+	leader, err := masterClient.GetLeader()
+	if err != nil {
+		return fmt.Errorf("failed to get leader from %s, error: %v", masterClient.GetURL(), err)
+	}
+	if !isMember(leader.AdvertiseAddr, podName) {
+		klog.Infof("tiflow-master %s is not the leader, leader: %s", podName, leader.AdvertiseAddr)
+		return nil
+	}
+
+	masters, err := masterClient.GetMasters()
+	if err != nil {
+		return fmt.Errorf("failed to get masters from %s, error: %v", masterClient.GetURL(), err)
+	}
+	if len(masters.Masters) <= 1 {
+		klog.Infof("tiflow-master %s is the only member, skip resigning", podName)
+		return nil
+	}
+
+	klog.Infof("resigning leadership of tiflow-master %s", podName)
+	if err = masterClient.EvictLeader(); err != nil {
+		return fmt.Errorf("failed to resign leadership of tiflow-master %s, error: %v", podName, err)
+	}
+	return waitUntil(deadline, func() (bool, error) {
+		leader, err := masterClient.GetLeader()
+		if err != nil {
+			// there is no leader during election
+			klog.Infof("failed to get leader, error: %v", err)
+			return false, nil
+		}
+		return !isMember(leader.AdvertiseAddr, podName), nil
+	}, fmt.Sprintf("tiflow-master %s resigns", podName))
+}
+
+// DrainExecutor removes tiflow-executor memberName from the tiflow-master, and waits until it's deregistered
+// and no job is waiting to be scheduled before the deadline, then stops it by stop, so its tasks are rescheduled
+// to the other executors before the pod is gone.
+func DrainExecutor(masterClient tiflowapi.MasterClient, memberName string, stop func() error, deadline time.Time) error {
+	registered := func() (*tiflowapi.Executor, error) {
+		executors, err := masterClient.GetExecutors()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get executors from %s, error: %v", masterClient.GetURL(), err)
+		}
+		for _, executor := range executors.Executors {
+			if executor.Name == memberName {
+				return executor, nil
+			}
+		}
+		return nil, nil
+	}
+
+	executor, err := registered()
+	if err != nil {
+		return err
+	}
+	if executor == nil {
+		klog.Infof("tiflow-executor %s is not registered, skip draining", memberName)
+		return nil
+	}
+
+	klog.Infof("draining tiflow-executor %s", memberName)
+	if err = masterClient.DeleteExecutor(executor.ID); err != nil {
+		return fmt.Errorf("failed to remove tiflow-executor %s from %s, error: %v", memberName, masterClient.GetURL(), err)
+	}
+	drainErr := waitUntil(deadline, func() (bool, error) {
+		executor, err := registered()
+		if err != nil {
+			klog.Info(err)
+			return false, nil
+		}
+		if executor != nil {
+			return false, nil
+		}
+		return jobsRescheduled(masterClient)
+	}, fmt.Sprintf("tiflow-executor %s is drained", memberName))
+
+	// the pod is going away anyway, so the executor is stopped even if draining times out
+	if err = stop(); err != nil {
+		return fmt.Errorf("failed to stop tiflow-executor %s, error: %v", memberName, err)
+	}
+	return drainErr
+}
+
+// jobsRescheduled returns whether no job is waiting to be scheduled to an executor
+func jobsRescheduled(masterClient tiflowapi.MasterClient) (bool, error) {
+	jobs, err := masterClient.GetJobs()
+	if err != nil {
+		klog.Infof("failed to get jobs from %s, error: %v", masterClient.GetURL(), err)
+		return false, nil
+	}
+	for _, job := range jobs.Jobs {
+		if job.State == tiflowapi.JobStateCreated {
+			klog.Infof("job %s is waiting to be rescheduled", job.ID)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// isMember returns whether the advertised address belongs to the pod
+func isMember(addr, podName string) bool {
+	return strings.HasPrefix(addr, podName+".")
+}
+
+func waitUntil(deadline time.Time, done func() (bool, error), desc string) error {
+	for {
+		ok, err := done()
+		if err != nil {
+			return err
+		}
+		if ok {
+			klog.Infof("%s", desc)
+			return nil
+		}
+		if !time.Now().Add(pollInterval).Before(deadline) {
+			return fmt.Errorf("timeout waiting until %s", desc)
+		}
+		time.Sleep(pollInterval)
+	}
+}
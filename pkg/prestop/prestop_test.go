@@ -0,0 +1,90 @@
+package prestop
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+func TestResignMaster(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	leader := "demo-tiflow-master-0.demo-tiflow-master-peer.ns.svc:10240"
+	resigned := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/leader":
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"advertise_addr":"%s"}`, leader)))
+		case "/api/v1/masters":
+			_, _ = w.Write([]byte(`{"masters":[{"name":"m0"},{"name":"m1"}]}`))
+		case "/api/v1/leader/resign":
+			resigned++
+			leader = "demo-tiflow-master-1.demo-tiflow-master-peer.ns.svc:10240"
+		}
+	}))
+	defer ts.Close()
+	masterClient := tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil)
+
+	// a follower has nothing to do
+	require.NoError(t, ResignMaster(masterClient, "demo-tiflow-master-1", time.Now().Add(time.Second)))
+	require.Equal(t, 0, resigned)
+
+	require.NoError(t, ResignMaster(masterClient, "demo-tiflow-master-0", time.Now().Add(time.Second)))
+	require.Equal(t, 1, resigned)
+}
+
+func TestDrainExecutor(t *testing.T) {
+	pollInterval = 10 * time.Millisecond
+	var registered, removable bool
+	jobState := "Running"
+	pending := 0
+	var requests []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/executors/e0":
+			requests = append(requests, "delete")
+			registered = !removable
+			jobState = "Created"
+		case r.URL.Path == "/api/v1/executors" && registered:
+			_, _ = w.Write([]byte(`{"executors":[{"id":"e0","name":"demo-tiflow-executor-0.ns"}]}`))
+		case r.URL.Path == "/api/v1/executors":
+			_, _ = w.Write([]byte(`{"executors":[]}`))
+		case r.URL.Path == "/api/v1/jobs":
+			// the job is rescheduled after it's waiting for a while
+			if jobState == "Created" && removable {
+				if pending++; pending > 2 {
+					requests = append(requests, "rescheduled")
+					jobState = "Running"
+				}
+			}
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"jobs":[{"id":"j0","state":"%s"}]}`, jobState)))
+		}
+	}))
+	defer ts.Close()
+	masterClient := tiflowapi.NewMasterClient(ts.URL, tiflowapi.DefaultTimeout, nil)
+	stop := func() error {
+		requests = append(requests, "stop")
+		return nil
+	}
+
+	// not registered at all
+	require.NoError(t, DrainExecutor(masterClient, "demo-tiflow-executor-0.ns", stop, time.Now().Add(time.Second)))
+	require.Empty(t, requests)
+
+	// the executor is never removed, it's still stopped once the deadline is reached
+	registered = true
+	err := DrainExecutor(masterClient, "demo-tiflow-executor-0.ns", stop, time.Now().Add(50*time.Millisecond))
+	require.Error(t, err)
+	require.Equal(t, []string{"delete", "stop"}, requests)
+
+	// the executor is stopped after it's removed and its job is rescheduled
+	requests = nil
+	removable = true
+	require.NoError(t, DrainExecutor(masterClient, "demo-tiflow-executor-0.ns", stop, time.Now().Add(time.Second)))
+	require.Equal(t, []string{"delete", "rescheduled", "stop"}, requests)
+}
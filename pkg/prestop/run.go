@@ -0,0 +1,132 @@
+package prestop
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+// BinaryName is the name of the operator binary installed into the pods of tiflow components
+const BinaryName = "manager"
+
+// Run handles the preStop hook of tiflow components, args are the command line arguments after `prestop`:
+//   - install: copies the operator binary into a directory shared with the tiflow container
+//   - master: resigns the leadership of the tiflow-master in the pod
+//   - executor: drains the tiflow-executor in the pod
+func Run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("one of install, master or executor should be given")
+	}
+
+	switch args[0] {
+	case "install":
+		return install(args[1:])
+	case "master", "executor":
+		return runHook(args[0], args[1:])
+	}
+	return fmt.Errorf("unknown prestop command %q", args[0])
+}
+
+func install(args []string) error {
+	var dir string
+	fs := flag.NewFlagSet("prestop install", flag.ContinueOnError)
+	fs.StringVar(&dir, "dir", "", "The directory which the operator binary is copied to.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if dir == "" {
+		return fmt.Errorf("--dir should be set")
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(self)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(filepath.Join(dir, BinaryName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func runHook(component string, args []string) error {
+	var tcName, namespace, podName, masterURL, certDir string
+	var timeout time.Duration
+	hostname, _ := os.Hostname()
+	fs := flag.NewFlagSet("prestop "+component, flag.ContinueOnError)
+	fs.StringVar(&tcName, "tc-name", "", "The name of tiflow cluster, required by tiflow-master.")
+	fs.StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of tiflow cluster.")
+	fs.StringVar(&podName, "pod-name", getEnv("POD_NAME", hostname), "The name of the pod.")
+	fs.StringVar(&masterURL, "master-url", "", "The url of tiflow-master which the tiflow-executor joins, required by tiflow-executor.")
+	fs.StringVar(&certDir, "tls-cert-dir", "", "The directory of client certificates of tiflow-master, TLS is disabled if it's empty.")
+	fs.DurationVar(&timeout, "timeout", 25*time.Second, "The max time to wait, it should be shorter than the termination grace period.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if namespace == "" || podName == "" {
+		return fmt.Errorf("both --namespace and --pod-name should be set")
+	}
+
+	scheme := "http"
+	var tlsConfig *tls.Config
+	if certDir != "" {
+		scheme = "https"
+		var err error
+		tlsConfig, err = tiflowapi.LoadTLSConfigFromDir(certDir)
+		if err != nil {
+			return err
+		}
+	}
+	deadline := time.Now().Add(timeout)
+
+	if component == "master" {
+		if tcName == "" {
+			return fmt.Errorf("--tc-name should be set")
+		}
+		// ask the tiflow-master in this pod, the leader is resigned only if it's the one receiving the request
+		masterClient := tiflowapi.NewMasterClient(tiflowapi.MasterClientURL(namespace, tcName, podName, scheme), tiflowapi.DefaultTimeout, tlsConfig)
+		return ResignMaster(masterClient, podName, deadline)
+	}
+
+	if masterURL == "" {
+		return fmt.Errorf("--master-url should be set")
+	}
+	if certDir == "" && strings.HasPrefix(masterURL, "https://") {
+		return fmt.Errorf("--tls-cert-dir should be set for %s", masterURL)
+	}
+	masterClient := tiflowapi.NewMasterClient(masterURL, tiflowapi.DefaultTimeout, tlsConfig)
+	// tiflow-executor is the main process of the container
+	stop := func() error {
+		p, err := os.FindProcess(1)
+		if err != nil {
+			return err
+		}
+		return p.Signal(syscall.SIGTERM)
+	}
+	// tiflow-executor registers itself with the name of `<pod>.<namespace>`
+	return DrainExecutor(masterClient, podName+"."+namespace, stop, deadline)
+}
+
+func getEnv(key, defaultValue string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return defaultValue
+}
@@ -0,0 +1,64 @@
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pingcap/tiflow-operator/api/label"
+)
+
+// Probe checks whether the tiflow component serving url is healthy, the client certificates in tlsConfig are
+// presented to the component, which can't be done by the HTTP probes of kubelet.
+func Probe(url string, tlsConfig *tls.Config, timeout time.Duration) error {
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to request %s, error: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %s of %s", resp.Status, url)
+	}
+	return nil
+}
+
+// InDebugMode returns whether the pod is in debug mode by the downward API file of its annotations,
+// the tiflow component isn't started by the start script then.
+func InDebugMode(annotationsFile string) bool {
+	data, err := os.ReadFile(annotationsFile)
+	if err != nil {
+		return false
+	}
+	debug := fmt.Sprintf("%s=%q", label.AnnRunModeKey, label.AnnRunModeDebug)
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == debug {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyLocalServer makes tlsConfig verify the certificate chain of the server only, the component is
+// requested by the loopback address, which isn't expected to be covered by its certificate.
+func verifyLocalServer(tlsConfig *tls.Config) {
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("no certificate is presented by the server")
+		}
+		opts := x509.VerifyOptions{Roots: tlsConfig.RootCAs, Intermediates: x509.NewCertPool()}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
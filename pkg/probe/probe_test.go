@@ -0,0 +1,59 @@
+package probe
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	ts.StartTLS()
+	defer ts.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(ts.Certificate())
+	tlsConfig := &tls.Config{RootCAs: rootCAs, Certificates: ts.TLS.Certificates}
+	verifyLocalServer(tlsConfig)
+
+	// the certificate of the server doesn't cover localhost, but its chain is still verified
+	url := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+	require.NoError(t, Probe(url+"/metrics", tlsConfig, time.Second))
+	require.Error(t, Probe(url+"/api/v1/leader", tlsConfig, time.Second))
+	require.Error(t, Probe(url+"/metrics", &tls.Config{RootCAs: rootCAs}, time.Second))
+	require.Error(t, Probe(url+"/metrics", &tls.Config{InsecureSkipVerify: true}, time.Second))
+
+	untrusted := &tls.Config{Certificates: ts.TLS.Certificates}
+	verifyLocalServer(untrusted)
+	untrusted.RootCAs = x509.NewCertPool()
+	require.Error(t, Probe(url+"/metrics", untrusted, time.Second))
+}
+
+func TestProbeInDebugMode(t *testing.T) {
+	annotationsFile := filepath.Join(t.TempDir(), "annotations")
+	require.NoError(t, os.WriteFile(annotationsFile, []byte("foo=\"bar\"\nrunmode=\"normal\"\n"), 0644))
+	require.False(t, InDebugMode(annotationsFile))
+	require.False(t, InDebugMode(filepath.Join(t.TempDir(), "absent")))
+
+	// nothing listens on the port, the probe only passes in debug mode
+	args := []string{"--port=1", "--timeout=100ms", "--annotations-file=" + annotationsFile}
+	require.Error(t, Run(args))
+
+	require.NoError(t, os.WriteFile(annotationsFile, []byte("foo=\"bar\"\nrunmode=\"debug\"\n"), 0644))
+	require.True(t, InDebugMode(annotationsFile))
+	require.NoError(t, Run(args))
+	require.Error(t, Run(args[:2]))
+}
@@ -0,0 +1,46 @@
+package probe
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiflow-operator/pkg/tiflowapi"
+)
+
+// Run checks the tiflow component in the pod, args are the command line arguments after `probe`.
+// It backs the default probes of the tiflow components serving with mutual TLS, which kubelet can't request.
+func Run(args []string) error {
+	var port int
+	var path, certDir, annotationsFile string
+	var timeout time.Duration
+	fs := flag.NewFlagSet("probe", flag.ContinueOnError)
+	fs.IntVar(&port, "port", 0, "The port of the tiflow component in the pod.")
+	fs.StringVar(&path, "path", "/", "The path to request.")
+	fs.StringVar(&certDir, "tls-cert-dir", "", "The directory of client certificates of the tiflow component, TLS is disabled if it's empty.")
+	fs.StringVar(&annotationsFile, "annotations-file", "", "The downward API file of the pod annotations, the probe passes while the pod is in debug mode if it's set.")
+	fs.DurationVar(&timeout, "timeout", 3*time.Second, "The max time to wait for the response.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if port == 0 {
+		return fmt.Errorf("--port should be set")
+	}
+	if annotationsFile != "" && InDebugMode(annotationsFile) {
+		return nil
+	}
+
+	scheme := "http"
+	var tlsConfig *tls.Config
+	if certDir != "" {
+		scheme = "https"
+		var err error
+		tlsConfig, err = tiflowapi.LoadTLSConfigFromDir(certDir)
+		if err != nil {
+			return err
+		}
+		verifyLocalServer(tlsConfig)
+	}
+	return Probe(fmt.Sprintf("%s://127.0.0.1:%d%s", scheme, port, path), tlsConfig, timeout)
+}
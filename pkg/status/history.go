@@ -0,0 +1,75 @@
+package status
+
+import (
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+// recordOperation keeps the history of operations in step with the sync type, so that it shows what the operator did
+// after the sync type is overwritten by the next operation of the same type.
+// An operation is started by Ongoing and ended by Completed or Failed, a failed operation retried by Ongoing
+// continues its record instead of starting a new one. Only the last MaxOperationHistory operations are kept.
+func recordOperation(history []v1alpha1.OperationRecord, syncName v1alpha1.SyncTypeName, syncStatus v1alpha1.SyncTypeStatus,
+	sts *apps.StatefulSetStatus, image, message string, now metav1.Time) []v1alpha1.OperationRecord {
+	opType := syncName.String()
+	var open, last *v1alpha1.OperationRecord
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Type != opType {
+			continue
+		}
+		if last == nil {
+			last = &history[i]
+		}
+		if history[i].EndTime == nil {
+			open = &history[i]
+			break
+		}
+	}
+	var replicas int32
+	if sts != nil {
+		replicas = sts.Replicas
+	}
+
+	switch syncStatus {
+	case v1alpha1.Ongoing:
+		if open == nil && last != nil && last == &history[len(history)-1] && last.Outcome == v1alpha1.Failed.String() {
+			open = last
+			open.EndTime = nil
+			open.Outcome = v1alpha1.Ongoing.String()
+		}
+		if open != nil {
+			open.Message = message
+			return history
+		}
+		history = append(history, v1alpha1.OperationRecord{
+			Type:         opType,
+			StartTime:    now,
+			FromReplicas: replicas,
+			FromImage:    image,
+			Outcome:      v1alpha1.Ongoing.String(),
+			Message:      message,
+		})
+		if len(history) > v1alpha1.MaxOperationHistory {
+			history = history[len(history)-v1alpha1.MaxOperationHistory:]
+		}
+		return history
+	case v1alpha1.Completed, v1alpha1.Failed:
+		if open == nil {
+			// an operation which failed before being started is recorded on its own
+			if syncStatus == v1alpha1.Completed || (last != nil && last.Outcome == v1alpha1.Failed.String() && last.Message == message) {
+				return history
+			}
+			history = recordOperation(history, syncName, v1alpha1.Ongoing, sts, image, message, now)
+			open = &history[len(history)-1]
+		}
+		end := now
+		open.EndTime = &end
+		open.ToReplicas = replicas
+		open.ToImage = image
+		open.Outcome = syncStatus.String()
+		open.Message = message
+	}
+	return history
+}
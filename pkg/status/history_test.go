@@ -0,0 +1,53 @@
+package status
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestOperationHistory(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{}
+	master := &tc.Status.Master
+	master.StatefulSet = &apps.StatefulSetStatus{Replicas: 3}
+	master.Image = "pingcap/tiflow:v6.5.0"
+
+	Ongoing(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "upgrading")
+	// a failed upgrade is continued by the retry
+	Failed(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "upgrading failed")
+	Ongoing(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "upgrading again")
+	require.Len(t, master.History, 1)
+	require.Nil(t, master.History[0].EndTime)
+
+	master.Image = "pingcap/tiflow:v6.6.0"
+	Completed(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "upgrading completed")
+	// completed again without being started
+	Completed(v1alpha1.UpgradeType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "upgrading completed")
+	require.Len(t, master.History, 1)
+	rec := master.History[0]
+	require.Equal(t, "Upgrade", rec.Type)
+	require.Equal(t, "Completed", rec.Outcome)
+	require.Equal(t, "pingcap/tiflow:v6.5.0", rec.FromImage)
+	require.Equal(t, "pingcap/tiflow:v6.6.0", rec.ToImage)
+	require.NotNil(t, rec.EndTime)
+
+	Ongoing(v1alpha1.ScaleOutType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "scaling out")
+	master.StatefulSet.Replicas = 5
+	Completed(v1alpha1.ScaleOutType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "scaling out completed")
+	require.Len(t, master.History, 2)
+	require.Equal(t, int32(3), master.History[1].FromReplicas)
+	require.Equal(t, int32(5), master.History[1].ToReplicas)
+
+	// the oldest operations are dropped
+	for i := 0; i < v1alpha1.MaxOperationHistory; i++ {
+		Ongoing(v1alpha1.ScaleInType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "scaling in")
+		Completed(v1alpha1.ScaleInType, tc.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, fmt.Sprintf("scaling in %d completed", i))
+	}
+	require.Len(t, master.History, v1alpha1.MaxOperationHistory)
+	require.Equal(t, "ScaleIn", master.History[0].Type)
+	require.Empty(t, tc.Status.Executor.History)
+}
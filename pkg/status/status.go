@@ -45,14 +45,29 @@ func Failed(syncName v1alpha1.SyncTypeName, tc *v1alpha1.TiflowClusterStatus, me
 	setExecutorSyncTypeStatus(syncName, v1alpha1.Failed, &tc.Executor, message, metav1.Now())
 }
 
+// Remove drops the sync type from the member's status, it's used when the sync type is no longer in effect
+func Remove(syncName v1alpha1.SyncTypeName, tc *v1alpha1.TiflowClusterStatus, member v1alpha1.MemberType) {
+	if member == v1alpha1.TiFlowMasterMemberType {
+		if pos := findPos(syncName, tc.Master.SyncTypes); pos >= 0 {
+			tc.Master.SyncTypes = append(tc.Master.SyncTypes[:pos], tc.Master.SyncTypes[pos+1:]...)
+		}
+		return
+	}
+	if pos := findPos(syncName, tc.Executor.SyncTypes); pos >= 0 {
+		tc.Executor.SyncTypes = append(tc.Executor.SyncTypes[:pos], tc.Executor.SyncTypes[pos+1:]...)
+	}
+}
+
 func setMasterSyncTypeStatus(syncName v1alpha1.SyncTypeName, syncStatus v1alpha1.SyncTypeStatus, master *v1alpha1.MasterStatus, message string, now metav1.Time) {
 	sync := findOrCreateMasterSyncType(syncName, master, message)
+	master.History = recordOperation(master.History, syncName, syncStatus, master.StatefulSet, master.Image, message, now)
 	sync.Status = syncStatus
 	sync.LastUpdateTime = now
 }
 
 func setExecutorSyncTypeStatus(syncName v1alpha1.SyncTypeName, syncStatus v1alpha1.SyncTypeStatus, executor *v1alpha1.ExecutorStatus, message string, now metav1.Time) {
 	sync := findOrCreateExecutorSyncType(syncName, executor, message)
+	executor.History = recordOperation(executor.History, syncName, syncStatus, executor.StatefulSet, executor.Image, message, now)
 	sync.Status = syncStatus
 	sync.LastUpdateTime = now
 }
@@ -3,6 +3,8 @@ package status
 import (
 	"context"
 	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
@@ -54,6 +56,14 @@ func (tcsm *TiflowClusterStatusManager) SyncTiflowClusterPhase() {
 
 	masterPhase, executorPhase := tcsm.cluster.GetMasterPhase(), tcsm.cluster.GetExecutorPhase()
 	switch {
+	case masterPhase == v1alpha1.MasterDeleting || executorPhase == v1alpha1.ExecutorDeleting:
+		tcsm.SetTiflowClusterPhase(v1alpha1.ClusterDeleting, "deleting... tearing down tiflow cluster")
+	case tcsm.cluster.IsPaused():
+		if masterPhase == v1alpha1.MasterSuspending || executorPhase == v1alpha1.ExecutorSuspending {
+			tcsm.SetTiflowClusterPhase(v1alpha1.ClusterSuspended, "suspending... scaling Master and Executor to zero")
+		} else {
+			tcsm.SetTiflowClusterPhase(v1alpha1.ClusterSuspended, "suspended, ConfigMaps, Services and PVCs are kept")
+		}
 	case masterPhase == v1alpha1.MasterFailed || executorPhase == v1alpha1.ExecutorFailed:
 		tcsm.SetTiflowClusterPhase(v1alpha1.ClusterFailed, "errors, failed phase for Master or Executor")
 	case masterPhase == v1alpha1.MasterUnknown || executorPhase == v1alpha1.ExecutorUnknown:
@@ -67,9 +77,58 @@ func (tcsm *TiflowClusterStatusManager) SyncTiflowClusterPhase() {
 
 func (tcsm *TiflowClusterStatusManager) SetTiflowClusterPhase(phase v1alpha1.TiflowClusterPhaseType, message string) {
 	clusterStatus := tcsm.cluster.GetClusterStatus()
+	if clusterStatus.ClusterPhase != phase {
+		clusterStatus.LastTransitionTime = metav1.Now()
+	}
 	clusterStatus.ClusterPhase = phase
 	clusterStatus.Message = message
-	clusterStatus.LastTransitionTime = metav1.Now()
+}
+
+// syncReadyCondition summarizes the cluster phase into the Ready condition,
+// the cluster is only ready when it's running as the latest spec acted on by the operator.
+func (tcsm *TiflowClusterStatusManager) syncReadyCondition() {
+	tc := tcsm.cluster
+	clusterStatus := tc.GetClusterStatus()
+	cond := metav1.Condition{
+		Type:               string(v1alpha1.Ready),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: clusterStatus.ObservedGeneration,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(clusterStatus.ClusterPhase),
+		Message:            clusterStatus.Message,
+	}
+	switch {
+	case clusterStatus.ClusterPhase == v1alpha1.ClusterDeleting:
+		// deleting bumps the generation, which is never acted on
+	case clusterStatus.ObservedGeneration < tc.Generation:
+		cond.Reason = v1alpha1.SpecNotObservedReason
+		cond.Message = fmt.Sprintf("generation %d is not acted on yet, the observed generation is %d",
+			tc.Generation, clusterStatus.ObservedGeneration)
+		if tc.ReconcilePaused() {
+			cond.Message += ", reconciling is paused by annotation"
+		}
+	case clusterStatus.ClusterPhase == v1alpha1.ClusterRunning:
+		cond.Status = metav1.ConditionTrue
+	}
+	meta.SetStatusCondition(&clusterStatus.ClusterConditions, cond)
+}
+
+// normalizeConditions fills the fields required by metav1.Condition, which may be missing from the conditions
+// recorded before migrating to it, otherwise the status is rejected by the API server.
+func (tcsm *TiflowClusterStatusManager) normalizeConditions() {
+	now := metav1.Now()
+	conds := tcsm.cluster.GetClusterStatus().ClusterConditions
+	for i := range conds {
+		if conds[i].Reason == "" {
+			conds[i].Reason = v1alpha1.ConditionUnsatisfiedReason
+			if conds[i].Status == metav1.ConditionTrue {
+				conds[i].Reason = v1alpha1.ConditionSatisfiedReason
+			}
+		}
+		if conds[i].LastTransitionTime.IsZero() {
+			conds[i].LastTransitionTime = now
+		}
+	}
 }
 
 func (tcsm TiflowClusterStatusManager) UpdateTiflowClusterPhase() error {
@@ -118,6 +177,8 @@ func (tcsm TiflowClusterStatusManager) UpdateTiflowClusterPhase() error {
 
 func (tcsm *TiflowClusterStatusManager) Update() error {
 	tcsm.SyncTiflowClusterPhase()
+	tcsm.normalizeConditions()
+	tcsm.syncReadyCondition()
 
 	if err := tcsm.UpdateTiflowClusterPhase(); err != nil {
 		klog.Errorf("update cluster phase error: %v", err)
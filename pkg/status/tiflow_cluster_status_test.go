@@ -0,0 +1,75 @@
+package status
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
+)
+
+func TestSyncReadyCondition(t *testing.T) {
+	tc := &v1alpha1.TiflowCluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "demo", Generation: 2}}
+	tcsm := &TiflowClusterStatusManager{cluster: tc}
+	tcsm.SetTiflowClusterPhase(v1alpha1.ClusterRunning, "running")
+	ready := func() *metav1.Condition {
+		tcsm.syncReadyCondition()
+		return meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.Ready))
+	}
+
+	// the latest spec isn't acted on
+	tc.Status.ObservedGeneration = 1
+	cond := ready()
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, v1alpha1.SpecNotObservedReason, cond.Reason)
+	require.Equal(t, int64(1), cond.ObservedGeneration)
+
+	tc.Status.ObservedGeneration = 2
+	cond = ready()
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, string(v1alpha1.ClusterRunning), cond.Reason)
+	require.Equal(t, int64(2), cond.ObservedGeneration)
+
+	// the phase transition time is kept while the phase doesn't change
+	transitioned := tc.Status.LastTransitionTime
+	tcsm.SetTiflowClusterPhase(v1alpha1.ClusterRunning, "still running")
+	require.Equal(t, transitioned, tc.Status.LastTransitionTime)
+
+	tcsm.SetTiflowClusterPhase(v1alpha1.ClusterReconciling, "reconciling")
+	cond = ready()
+	require.Equal(t, metav1.ConditionFalse, cond.Status)
+	require.Equal(t, string(v1alpha1.ClusterReconciling), cond.Reason)
+	require.Equal(t, "reconciling", cond.Message)
+}
+
+func TestNormalizeConditions(t *testing.T) {
+	// the status recorded before migrating to metav1.Condition, reason and lastTransitionTime were optional
+	tc := &v1alpha1.TiflowCluster{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"metadata": {"namespace": "ns-1", "name": "demo", "generation": 1},
+		"status": {"clusterPhase": "Running", "clusterConditions": [
+			{"type": "MasterSyncChecked", "status": "True", "lastTransitionTime": "2022-06-01T00:00:00Z"},
+			{"type": "ExecutorSyncChecked", "status": "False", "lastTransitionTime": null},
+			{"type": "ReferencedMasterChecked", "status": "False", "reason": "ReferencedMasterNotReady"}
+		]}
+	}`), tc))
+	tcsm := &TiflowClusterStatusManager{cluster: tc}
+	tcsm.normalizeConditions()
+	tcsm.syncReadyCondition()
+
+	for _, cond := range tc.Status.ClusterConditions {
+		require.NotEmpty(t, cond.Reason, cond.Type)
+		require.False(t, cond.LastTransitionTime.IsZero(), cond.Type)
+	}
+	cond := meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.MasterSyncChecked))
+	require.Equal(t, v1alpha1.ConditionSatisfiedReason, cond.Reason)
+	require.Equal(t, 2022, cond.LastTransitionTime.Year())
+	cond = meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.ExecutorSyncChecked))
+	require.Equal(t, v1alpha1.ConditionUnsatisfiedReason, cond.Reason)
+	cond = meta.FindStatusCondition(tc.Status.ClusterConditions, string(v1alpha1.ReferencedMasterChecked))
+	require.Equal(t, v1alpha1.ReferencedMasterNotReadyReason, cond.Reason)
+	require.Len(t, tc.Status.ClusterConditions, 4)
+}
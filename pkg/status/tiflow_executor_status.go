@@ -61,6 +61,11 @@ func (em *executorPhaseManger) SyncPhase() {
 
 	InitExecutorClusterSyncTypesIfNeed(executorStatus)
 
+	if em.IsPaused() {
+		em.syncExecutorSuspendPhase()
+		return
+	}
+
 	if conditionIsTrue(v1alpha1.ExecutorSyncChecked, em.GetClusterConditions()) &&
 		!em.syncExecutorPhaseFromCluster() {
 		return
@@ -92,6 +97,10 @@ func (em *executorPhaseManger) SyncPhase() {
 }
 
 func (em *executorPhaseManger) syncExecutorPhaseFromCluster() bool {
+	if em.syncExecutorResumePhase() {
+		return true
+	}
+
 	if em.syncExecutorCreatePhase() {
 		return true
 	}
@@ -107,6 +116,43 @@ func (em *executorPhaseManger) syncExecutorPhaseFromCluster() bool {
 	return false
 
 }
+
+// syncExecutorSuspendPhase derives the phase of a paused tiflow-executor from the progress of suspending
+func (em *executorPhaseManger) syncExecutorSuspendPhase() {
+	executorStatus := em.GetExecutorStatus()
+
+	syncTypes := em.GetExecutorSyncTypes()
+	index := findPos(v1alpha1.SuspendType, syncTypes)
+	switch {
+	case index < 0 || syncTypes[index].Status == v1alpha1.Ongoing || syncTypes[index].Status == v1alpha1.Unknown:
+		executorStatus.Phase = v1alpha1.ExecutorSuspending
+		executorStatus.Message = "Suspending..., tiflow-executor is scaling to zero. Just a moment"
+	case syncTypes[index].Status == v1alpha1.Failed:
+		executorStatus.Phase = v1alpha1.ExecutorFailed
+		executorStatus.Message = syncTypes[index].Message
+	default:
+		executorStatus.Phase = v1alpha1.ExecutorSuspended
+		executorStatus.Message = "Suspended..., tiflow-executor is scaled to zero"
+	}
+	executorStatus.LastTransitionTime = metav1.Now()
+}
+
+// syncExecutorResumePhase return true indicates the tiflow-executor has been resumed
+func (em *executorPhaseManger) syncExecutorResumePhase() bool {
+	syncTypes := em.GetExecutorSyncTypes()
+	index := findPos(v1alpha1.ResumeType, syncTypes)
+	if index < 0 || syncTypes[index].Status == v1alpha1.Completed {
+		return false
+	}
+
+	if em.ExecutorStsDesiredReplicas() == em.ExecutorStsReadyReplicas() {
+		Completed(v1alpha1.ResumeType, em.GetClusterStatus(), v1alpha1.TiFlowExecutorMemberType, "executor resuming completed")
+		return true
+	}
+
+	return false
+}
+
 func (em *executorPhaseManger) syncExecutorCreatePhase() bool {
 	syncTypes := em.GetExecutorSyncTypes()
 	index := findPos(v1alpha1.CreateType, syncTypes)
@@ -155,6 +201,10 @@ func (em *executorPhaseManger) syncExecutorUpgradePhase() bool {
 	if index < 0 || syncTypes[index].Status == v1alpha1.Completed {
 		return false
 	}
+	// a rolled back upgrade stays failed until the spec is changed
+	if em.GetExecutorStatus().Upgrade.RolledBack() {
+		return false
+	}
 
 	ns := em.GetNamespace()
 	tcName := em.GetName()
@@ -61,6 +61,11 @@ func (mm *masterPhaseManager) SyncPhase() {
 
 	InitMasterClusterSyncTypesIfNeed(masterStatus)
 
+	if mm.IsPaused() {
+		mm.syncMasterSuspendPhase()
+		return
+	}
+
 	if conditionIsTrue(v1alpha1.MasterSyncChecked, mm.GetClusterConditions()) &&
 		!mm.syncMasterPhaseFromCluster() {
 		return
@@ -93,6 +98,10 @@ func (mm *masterPhaseManager) SyncPhase() {
 
 func (mm *masterPhaseManager) syncMasterPhaseFromCluster() bool {
 
+	if mm.syncMasterResumePhase() {
+		return true
+	}
+
 	if mm.syncMasterCreatePhase() {
 		return true
 	}
@@ -107,6 +116,43 @@ func (mm *masterPhaseManager) syncMasterPhaseFromCluster() bool {
 
 	return false
 }
+
+// syncMasterSuspendPhase derives the phase of a paused tiflow-master from the progress of suspending
+func (mm *masterPhaseManager) syncMasterSuspendPhase() {
+	masterStatus := mm.GetMasterStatus()
+
+	syncTypes := mm.GetMasterSyncTypes()
+	index := findPos(v1alpha1.SuspendType, syncTypes)
+	switch {
+	case index < 0 || syncTypes[index].Status == v1alpha1.Ongoing || syncTypes[index].Status == v1alpha1.Unknown:
+		masterStatus.Phase = v1alpha1.MasterSuspending
+		masterStatus.Message = "Suspending..., tiflow-master is scaling to zero. Just a moment"
+	case syncTypes[index].Status == v1alpha1.Failed:
+		masterStatus.Phase = v1alpha1.MasterFailed
+		masterStatus.Message = syncTypes[index].Message
+	default:
+		masterStatus.Phase = v1alpha1.MasterSuspended
+		masterStatus.Message = "Suspended..., tiflow-master is scaled to zero"
+	}
+	masterStatus.LastTransitionTime = metav1.Now()
+}
+
+// syncMasterResumePhase return true indicates the tiflow-master has been resumed
+func (mm *masterPhaseManager) syncMasterResumePhase() bool {
+	syncTypes := mm.GetMasterSyncTypes()
+	index := findPos(v1alpha1.ResumeType, syncTypes)
+	if index < 0 || syncTypes[index].Status == v1alpha1.Completed {
+		return false
+	}
+
+	if mm.MasterStsDesiredReplicas() == mm.MasterStsReadyReplicas() {
+		Completed(v1alpha1.ResumeType, mm.GetClusterStatus(), v1alpha1.TiFlowMasterMemberType, "master resuming completed")
+		return true
+	}
+
+	return false
+}
+
 func (mm *masterPhaseManager) syncMasterCreatePhase() bool {
 	syncTypes := mm.GetMasterSyncTypes()
 	index := findPos(v1alpha1.CreateType, syncTypes)
@@ -155,6 +201,10 @@ func (mm *masterPhaseManager) syncMasterUpgradePhase() bool {
 	if index < 0 || syncTypes[index].Status == v1alpha1.Completed {
 		return false
 	}
+	// a rolled back upgrade stays failed until the spec is changed
+	if mm.GetMasterStatus().Upgrade.RolledBack() {
+		return false
+	}
 
 	ns := mm.GetNamespace()
 	tcName := mm.GetName()
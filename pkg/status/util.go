@@ -2,6 +2,7 @@ package status
 
 import (
 	"fmt"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -38,22 +39,6 @@ func isUpgrading(set *appsv1.StatefulSet) bool {
 	return false
 }
 
-func conditionIsTrue(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) bool {
-
-	index := pos(ctype, conds)
-	if index == -1 || conds[index].Status == metav1.ConditionUnknown {
-		return false
-	}
-
-	return conds[index].Status == metav1.ConditionTrue
-}
-
-func pos(ctype v1alpha1.TiflowClusterConditionType, conds []v1alpha1.TiflowClusterCondition) int {
-	for i := range conds {
-		if conds[i].Type == ctype {
-			return i
-		}
-	}
-
-	return -1
+func conditionIsTrue(ctype v1alpha1.TiflowClusterConditionType, conds []metav1.Condition) bool {
+	return meta.IsStatusConditionTrue(conds, string(ctype))
 }
@@ -123,9 +123,13 @@ func (s Sandbox) StartManager(t *testing.T) {
 	clientSet, err := kubernetes.NewForConfig(s.Mgr.GetConfig())
 	require.NoError(t, err)
 
-	reconciler := controllers.NewTiflowClusterReconciler(s.Mgr.GetClient(), clientSet, s.Mgr.GetScheme())
+	reconciler := controllers.NewTiflowClusterReconciler(s.Mgr.GetClient(), clientSet, s.Mgr.GetScheme(),
+		s.Mgr.GetEventRecorderFor("tiflow-operator"))
 	require.NoError(t, reconciler.SetupWithManager(s.Mgr))
 
+	monitorReconciler := controllers.NewTiflowMonitorReconciler(s.Mgr.GetClient(), s.Mgr.GetScheme())
+	require.NoError(t, monitorReconciler.SetupWithManager(s.Mgr))
+
 	standaloneReconcile := controllers.NewStandaloneReconciler(s.Mgr.GetClient(), s.Mgr.GetScheme())
 	require.NoError(t, standaloneReconcile.SetupWithManager(s.Mgr), err)
 
@@ -6,6 +6,7 @@ import (
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/pingcap/tiflow-operator/api/v1alpha1"
 	"github.com/pingcap/tiflow-operator/pkg/util"
 )
 
@@ -31,6 +32,93 @@ func GetMasterClient(cli client.Client, namespace, tcName, podName string, tlsEn
 	return NewMasterClient(MasterClientURL(namespace, tcName, podName, scheme), DefaultTimeout, nil)
 }
 
+// GetMasterMemberClient provides a MasterClient of the tiflow-master member advertising the address,
+// the member may belong to a peer cluster sharing the cluster client certificates of tc
+func GetMasterMemberClient(cli client.Client, namespace, tcName, address string, tlsEnabled bool) MasterClient {
+	if !tlsEnabled {
+		return NewMasterClient(fmt.Sprintf("http://%s", address), DefaultTimeout, nil)
+	}
+
+	tlsConfig, err := GetTLSConfig(cli, namespace, util.ClusterClientTLSSecretName(tcName))
+	if err != nil {
+		klog.Errorf("Unable to get tls config for tiflow cluster %q, master client of %s may not work: %v", tcName, address, err)
+	}
+	return NewMasterClient(fmt.Sprintf("https://%s", address), DefaultTimeout, tlsConfig)
+}
+
+// GetRemoteMasterClient provides a MasterClient of tiflow-master cluster in another Kubernetes cluster
+// addresses: the externally reachable host:port of tiflow-master, the first one is used
+// secretName != "": the secret which stores the client certificates of the remote tiflow-master
+func GetRemoteMasterClient(cli client.Client, namespace, secretName string, addresses []string) MasterClient {
+	if len(addresses) == 0 {
+		klog.Errorf("No address of remote tiflow-master is given, master client may not work")
+		return NewMasterClient("", DefaultTimeout, nil)
+	}
+
+	if secretName == "" {
+		return NewMasterClient(fmt.Sprintf("http://%s", addresses[0]), DefaultTimeout, nil)
+	}
+
+	tlsConfig, err := GetTLSConfig(cli, namespace, secretName)
+	if err != nil {
+		klog.Errorf("Unable to get tls config for remote tiflow-master %v, master client may not work: %v", addresses, err)
+	}
+	return NewMasterClient(fmt.Sprintf("https://%s", addresses[0]), DefaultTimeout, tlsConfig)
+}
+
+// GetJoinedMasterClient provides a MasterClient of tiflow-master cluster which the executors of tc join.
+// It's the local one, the one of referenced cluster or the remote one set by spec.cluster.masterAddresses.
+func GetJoinedMasterClient(cli client.Client, tc *v1alpha1.TiflowCluster) MasterClient {
+	if !tc.Heterogeneous() || !tc.WithoutLocalMaster() {
+		return GetMasterClient(cli, tc.GetNamespace(), tc.GetName(), "", tc.IsClusterTLSEnabled())
+	}
+
+	if tc.RemoteMaster() {
+		return GetRemoteMasterClient(cli, tc.GetNamespace(), RemoteMasterTLSSecretName(tc), tc.Spec.Cluster.MasterAddresses)
+	}
+
+	refNs, refName := tc.ReferencedCluster()
+	return GetMasterClient(cli, refNs, refName, "", tc.IsClusterTLSEnabled())
+}
+
+// JoinedMasterURL returns the url of tiflow-master cluster which the executors of tc join, see GetJoinedMasterClient
+func JoinedMasterURL(tc *v1alpha1.TiflowCluster) string {
+	scheme := "http"
+	if tc.IsClusterTLSEnabled() {
+		scheme = "https"
+	}
+	if !tc.Heterogeneous() || !tc.WithoutLocalMaster() {
+		return MasterClientURL(tc.GetNamespace(), tc.GetName(), "", scheme)
+	}
+
+	if tc.RemoteMaster() {
+		scheme = "http"
+		if RemoteMasterTLSSecretName(tc) != "" {
+			scheme = "https"
+		}
+		return fmt.Sprintf("%s://%s", scheme, tc.Spec.Cluster.MasterAddresses[0])
+	}
+
+	refNs, refName := tc.ReferencedCluster()
+	return MasterClientURL(refNs, refName, "", scheme)
+}
+
+// RemoteMasterTLSSecretName returns the secret storing the client certificates of the remote tiflow-master
+// which the executors of tc join, it's the client secret of the referenced cluster copied to the namespace of tc
+// unless spec.cluster.tlsClientSecretName is set. It's empty if TLS is not used.
+func RemoteMasterTLSSecretName(tc *v1alpha1.TiflowCluster) string {
+	if !tc.RemoteMaster() {
+		return ""
+	}
+	if tc.Spec.Cluster.TLSClientSecretName != "" {
+		return tc.Spec.Cluster.TLSClientSecretName
+	}
+	if tc.IsClusterTLSEnabled() {
+		return util.ClusterClientTLSSecretName(tc.Spec.Cluster.Name)
+	}
+	return ""
+}
+
 // MasterClientURL builds the url of master client
 func MasterClientURL(namespace, clusterName, podName, scheme string) string {
 	peer := ""
@@ -14,6 +14,16 @@ const (
 	DefaultTimeout = 5 * time.Second
 )
 
+// Observer is called after every request to tiflow-master API with its latency and result,
+// it's set by the operator to collect metrics and left nil by the other modes of the binary.
+var Observer func(api string, start time.Time, err error)
+
+func observe(api string, start time.Time, err error) {
+	if Observer != nil {
+		Observer(api, start, err)
+	}
+}
+
 // MasterClient provides master server's api
 type MasterClient interface {
 	// GetMasters returns all master members from cluster
@@ -23,7 +33,11 @@ type MasterClient interface {
 	GetURL() string
 	EvictLeader() error
 	DeleteMaster(name string) error
-	DeleteExecutor(name string) error
+	// DeleteExecutor removes the executor from the cluster, its tasks are rescheduled to the other executors
+	DeleteExecutor(id string) error
+	// GetJobs returns all jobs submitted to the cluster
+	GetJobs() (JobsInfo, error)
+	CancelJob(id string) error
 }
 
 const (
@@ -31,6 +45,9 @@ const (
 	leaderResignPrefix  = "api/v1/leader/resign"
 	listMastersPrefix   = "api/v1/masters"
 	listExecutorsPrefix = "api/v1/executors"
+	executorPattern     = "api/v1/executors/%s"
+	listJobsPrefix      = "api/v1/jobs"
+	cancelJobPattern    = "api/v1/jobs/%s/cancel"
 )
 
 type Master struct {
@@ -55,6 +72,34 @@ type ExecutorsInfo struct {
 	Executors []*Executor `json:"executors,omitempty"`
 }
 
+// Job states reported by tiflow-master, a job in Finished, Failed or Canceled will never be scheduled again
+const (
+	JobStateCreated   = "Created"
+	JobStateFinished  = "Finished"
+	JobStateFailed    = "Failed"
+	JobStateCanceling = "Canceling"
+	JobStateCanceled  = "Canceled"
+)
+
+type Job struct {
+	ID    string `json:"id,omitempty"`
+	Type  string `json:"type,omitempty"`
+	State string `json:"state,omitempty"`
+}
+
+// IsTerminated returns whether the job has stopped running
+func (j *Job) IsTerminated() bool {
+	switch j.State {
+	case JobStateFinished, JobStateFailed, JobStateCanceled:
+		return true
+	}
+	return false
+}
+
+type JobsInfo struct {
+	Jobs []*Job `json:"jobs,omitempty"`
+}
+
 type LeaderInfo struct {
 	AdvertiseAddr string `json:"advertise_addr,omitempty"`
 }
@@ -71,7 +116,9 @@ func (c masterClient) GetURL() string {
 
 func (c masterClient) GetMasters() (MastersInfo, error) {
 	apiURL := fmt.Sprintf("%s/%s", c.url, listMastersPrefix)
+	start := time.Now()
 	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	observe("GetMasters", start, err)
 	if err != nil {
 		return MastersInfo{}, err
 	}
@@ -83,7 +130,9 @@ func (c masterClient) GetMasters() (MastersInfo, error) {
 
 func (c masterClient) GetExecutors() (ExecutorsInfo, error) {
 	apiURL := fmt.Sprintf("%s/%s", c.url, listExecutorsPrefix)
+	start := time.Now()
 	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	observe("GetExecutors", start, err)
 	if err != nil {
 		return ExecutorsInfo{}, err
 	}
@@ -95,7 +144,9 @@ func (c masterClient) GetExecutors() (ExecutorsInfo, error) {
 
 func (c masterClient) GetLeader() (LeaderInfo, error) {
 	apiURL := fmt.Sprintf("%s/%s", c.url, leaderPrefix)
+	start := time.Now()
 	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	observe("GetLeader", start, err)
 	if err != nil {
 		return LeaderInfo{}, err
 	}
@@ -106,7 +157,9 @@ func (c masterClient) GetLeader() (LeaderInfo, error) {
 
 func (c masterClient) EvictLeader() error {
 	apiURL := fmt.Sprintf("%s/%s", c.url, leaderResignPrefix)
+	start := time.Now()
 	_, err := httputil.PostBodyOK(c.httpClient, apiURL, nil)
+	observe("EvictLeader", start, err)
 	return err
 }
 
@@ -115,9 +168,34 @@ func (c masterClient) DeleteMaster(name string) error {
 	panic("implement me")
 }
 
-func (c masterClient) DeleteExecutor(name string) error {
-	// TODO implement me
-	panic("implement me")
+func (c masterClient) DeleteExecutor(id string) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, fmt.Sprintf(executorPattern, id))
+	start := time.Now()
+	_, err := httputil.DeleteBodyOK(c.httpClient, apiURL)
+	observe("DeleteExecutor", start, err)
+	return err
+}
+
+func (c masterClient) GetJobs() (JobsInfo, error) {
+	apiURL := fmt.Sprintf("%s/%s", c.url, listJobsPrefix)
+	start := time.Now()
+	body, err := httputil.GetBodyOK(c.httpClient, apiURL)
+	observe("GetJobs", start, err)
+	if err != nil {
+		return JobsInfo{}, err
+	}
+
+	var jobs JobsInfo
+	err = json.Unmarshal(body, &jobs)
+	return jobs, err
+}
+
+func (c masterClient) CancelJob(id string) error {
+	apiURL := fmt.Sprintf("%s/%s", c.url, fmt.Sprintf(cancelJobPattern, id))
+	start := time.Now()
+	_, err := httputil.PostBodyOK(c.httpClient, apiURL, nil)
+	observe("CancelJob", start, err)
+	return err
 }
 
 // NewMasterClient returns a new MasterClient
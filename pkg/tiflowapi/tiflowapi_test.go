@@ -0,0 +1,38 @@
+package tiflowapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/leader/resign" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"advertise_addr":"demo-tiflow-master-0.demo-tiflow-master-peer.ns-1.svc:10240"}`))
+	}))
+	defer ts.Close()
+	masterClient := NewMasterClient(ts.URL, DefaultTimeout, nil)
+
+	// nothing is observed by default
+	_, err := masterClient.GetLeader()
+	require.NoError(t, err)
+
+	observed := map[string]error{}
+	Observer = func(api string, start time.Time, err error) {
+		observed[api] = err
+	}
+	defer func() { Observer = nil }()
+	_, err = masterClient.GetLeader()
+	require.NoError(t, err)
+	require.Error(t, masterClient.EvictLeader())
+	require.Len(t, observed, 2)
+	require.NoError(t, observed["GetLeader"])
+	require.Error(t, observed["EvictLeader"])
+}
@@ -5,6 +5,8 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"os"
+	"path/filepath"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -49,3 +51,16 @@ func LoadTlsConfigFromSecret(secret *corev1.Secret) (*tls.Config, error) {
 		Certificates: []tls.Certificate{tlsCert},
 	}, nil
 }
+
+// LoadTLSConfigFromDir returns *tls.Config from the certificates mounted in the same layout as the TLS secret
+func LoadTLSConfigFromDir(dir string) (*tls.Config, error) {
+	secret := &corev1.Secret{Data: map[string][]byte{}}
+	for _, key := range []string{corev1.ServiceAccountRootCAKey, corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate %s from %s, error: %v", key, dir, err)
+		}
+		secret.Data[key] = data
+	}
+	return LoadTlsConfigFromSecret(secret)
+}